@@ -0,0 +1,397 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/lookup"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AgentCmd Runs the Agent
+var AgentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Runs an Agent that keeps the unlocked Private Key and a logged in Session in Memory",
+	Long: `Runs an Agent in the foreground that keeps the unlocked Private Key and a logged in Session in Memory,
+like ssh-agent. Other Invocations of the CLI by the same User are sent to the Agent via a Unix Socket and run
+there, so they need neither the Passphrase nor a new Login. The Passphrase never leaves the Agent.
+exec and render run locally and get the decrypted Resources they reference from the Agent, commands with
+--watch run locally as they run until interrupted. An Invocation is stopped when its CLI exits or the Agent is locked.
+
+The Agent starts locked, use "passbolt agent unlock" to enter the Passphrase and complete the MFA Challenge once.
+It locks itself and logs out after being idle for --idleTimeout or with "passbolt agent lock".
+Invocations with --userPassword, for another Server or another Private Key are not sent to the Agent.
+
+The Socket is created in a Directory that only the current User may access, the Agent refuses to start otherwise.
+Run it in the background with your shell, e.g. "passbolt agent &", or as a systemd user service.`,
+	Args: cobra.NoArgs,
+	RunE: Agent,
+}
+
+// Execute runs an Invocation of the CLI inside the Agent and returns its exit
+// code, it is set by the cmd package. ctx ends when the CLI disconnects.
+var Execute func(ctx context.Context, args []string) int
+
+func init() {
+	AgentCmd.Flags().Duration("idleTimeout", time.Hour, "Lock after this long without a Request, 0 disables the Timeout")
+
+	AgentCmd.AddCommand(AgentLockCmd)
+	AgentCmd.AddCommand(AgentUnlockCmd)
+	AgentCmd.AddCommand(AgentStatusCmd)
+}
+
+func Agent(cmd *cobra.Command, args []string) error {
+	idleTimeout, err := cmd.Flags().GetDuration("idleTimeout")
+	if err != nil {
+		return err
+	}
+
+	if !peerCredentialsSupported {
+		return fmt.Errorf("the Agent is not supported on %v", runtime.GOOS)
+	}
+	privateKey := viper.GetString("userPrivateKey")
+	if privateKey == "" {
+		return fmt.Errorf("userPrivateKey is not defined")
+	}
+	serverAddress := viper.GetString("serverAddress")
+	if serverAddress == "" {
+		return fmt.Errorf("serverAddress is not defined")
+	}
+
+	path, err := util.AgentSocketPath()
+	if err != nil {
+		return err
+	}
+	err = prepareSocketDir(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode().Type() != fs.ModeSocket {
+			return fmt.Errorf("%v exists and is not a Socket", path)
+		}
+		if _, err := util.CallAgent(util.AgentRequest{Command: "status"}); err == nil {
+			return fmt.Errorf("an Agent is already running on %v", path)
+		}
+		// Left over from an Agent that did not shut down cleanly
+		os.Remove(path)
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return fmt.Errorf("listening on %v: %w", path, err)
+	}
+	defer listener.Close()
+	cmd.SilenceUsage = true
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	s := &server{
+		keyID:       util.AgentKeyID(privateKey),
+		server:      serverAddress,
+		uid:         os.Getuid(),
+		idleTimeout: idleTimeout,
+		config:      viper.AllSettings(),
+		log:         os.Stderr,
+	}
+	defer s.lock()
+
+	s.logf("Agent listening on %v, run \"passbolt agent unlock\" to unlock it\n", path)
+	for {
+		conn, err := listener.AcceptUnix()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accepting Connection: %w", err)
+		}
+		go s.handle(conn)
+	}
+}
+
+// server holds the logged in client while unlocked
+type server struct {
+	keyID       string
+	server      string
+	uid         int
+	idleTimeout time.Duration
+	// config are the settings the agent was started with, invocations
+	// run by the agent replace them
+	config map[string]any
+	// log is the agents own stderr, which is replaced while running an invocation
+	log io.Writer
+
+	// runMu serializes everything using the client or the process state
+	runMu sync.Mutex
+
+	mu        sync.Mutex
+	client    *api.Client
+	idleTimer *time.Timer
+	// cancelRun stops the running invocation, if there is one
+	cancelRun context.CancelFunc
+}
+
+func (s *server) logf(format string, a ...any) {
+	fmt.Fprintf(s.log, format, a...)
+}
+
+func (s *server) handle(conn *net.UnixConn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second * 10))
+
+	uid, err := peerUID(conn)
+	if err != nil {
+		s.logf("Rejected Connection: %v\n", err)
+		return
+	}
+	if uid != s.uid {
+		s.logf("Rejected Connection from uid %v\n", uid)
+		return
+	}
+
+	st := newStream(conn)
+	var req util.AgentRequest
+	err = st.dec.Decode(&req)
+	if err != nil {
+		return
+	}
+	// Invocations may run and wait for input for a long time, but not
+	// longer than the CLI that asked for them
+	conn.SetDeadline(time.Time{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go st.receive(ctx, cancel)
+	st.send(s.answer(ctx, req, st))
+}
+
+func (s *server) answer(ctx context.Context, req util.AgentRequest, st *stream) util.AgentResponse {
+	switch req.Command {
+	case "status":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		locked := s.client == nil || (req.KeyID != "" && !s.matches(req))
+		return util.AgentResponse{Locked: locked, IdleTimeout: s.idleTimeout}
+	case "lock":
+		s.stopRun()
+		s.lock()
+		return util.AgentResponse{Locked: true}
+	case "unlock":
+		err := s.unlock(ctx, req.Passphrase, st)
+		if err != nil {
+			return util.AgentResponse{Locked: s.locked(), Error: err.Error()}
+		}
+		return util.AgentResponse{}
+	case "run":
+		return s.serve(ctx, req, st, func(ctx context.Context) (util.AgentResponse, error) {
+			if Execute == nil {
+				return util.AgentResponse{}, errors.New("the Agent can't run Invocations")
+			}
+			restore, err := useEnvironment(req.Env, req.Dir)
+			if err != nil {
+				return util.AgentResponse{}, err
+			}
+			defer restore()
+			return util.AgentResponse{ExitCode: Execute(ctx, req.Args)}, nil
+		})
+	case "resolve":
+		return s.serve(ctx, req, st, func(ctx context.Context) (util.AgentResponse, error) {
+			ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("timeout"))
+			defer cancel()
+			client, err := s.session(ctx)
+			if err != nil {
+				return util.AgentResponse{}, err
+			}
+			id, err := util.NewResolver(client).Resource(ctx, req.Ref)
+			return util.AgentResponse{ID: id}, err
+		})
+	case "resource":
+		return s.serve(ctx, req, st, func(ctx context.Context) (util.AgentResponse, error) {
+			ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("timeout"))
+			defer cancel()
+			client, err := s.session(ctx)
+			if err != nil {
+				return util.AgentResponse{}, err
+			}
+			r, err := lookup.New(client).Resource(ctx, req.Ref)
+			if err != nil {
+				return util.AgentResponse{}, err
+			}
+			data, err := json.Marshal(r)
+			return util.AgentResponse{Resource: data}, err
+		})
+	}
+	return util.AgentResponse{Error: fmt.Sprintf("unknown command %q", req.Command)}
+}
+
+// matches returns if req is for the account of the agent, s.mu must be held
+func (s *server) matches(req util.AgentRequest) bool {
+	return req.KeyID == s.keyID && req.Server == s.server
+}
+
+// serve runs fn for a request that needs the client, with the output and
+// prompts of fn sent to the CLI. fn is canceled when the CLI disconnects or
+// the agent is locked.
+func (s *server) serve(ctx context.Context, req util.AgentRequest, st *stream, fn func(ctx context.Context) (util.AgentResponse, error)) util.AgentResponse {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	if s.client == nil || !s.matches(req) {
+		s.mu.Unlock()
+		return util.AgentResponse{Locked: true}
+	}
+	s.touch()
+	s.cancelRun = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.cancelRun = nil
+		s.mu.Unlock()
+	}()
+	if viper.GetBool("debug") {
+		s.logf("Handling %v Request\n", req.Command)
+	}
+
+	res, err := s.captured(ctx, st, fn)
+	s.restoreConfig()
+	if err != nil {
+		return util.AgentResponse{Error: err.Error()}
+	}
+	return res
+}
+
+// captured runs fn with its output, prompts and client going through the agent
+func (s *server) captured(ctx context.Context, st *stream, fn func(ctx context.Context) (util.AgentResponse, error)) (util.AgentResponse, error) {
+	c, err := captureOutput(st)
+	if err != nil {
+		return util.AgentResponse{}, err
+	}
+	defer c.restore()
+	util.SetAgentRun(&util.AgentRun{Context: ctx, Client: s.session, Prompt: c.prompt})
+	defer util.SetAgentRun(nil)
+	return fn(ctx)
+}
+
+// session returns the client, logging in again if the session has ended
+func (s *server) session(ctx context.Context) (*api.Client, error) {
+	s.mu.Lock()
+	client := s.client
+	s.mu.Unlock()
+	if client == nil {
+		return nil, errors.New("the Agent is locked")
+	}
+	if !client.CheckSession(ctx) {
+		err := client.Login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("logging in again: %w", err)
+		}
+	}
+	return client, nil
+}
+
+// restoreConfig replaces the settings of the last invocation with those of the agent
+func (s *server) restoreConfig() {
+	viper.Reset()
+	for k, v := range s.config {
+		viper.Set(k, v)
+	}
+}
+
+func (s *server) locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client == nil
+}
+
+// touch restarts the idle timeout, s.mu must be held
+func (s *server) touch() {
+	if s.idleTimer != nil {
+		s.idleTimer.Reset(s.idleTimeout)
+	}
+}
+
+// stopRun cancels the running invocation, so lock does not wait for it
+func (s *server) stopRun() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancelRun != nil {
+		s.cancelRun()
+	}
+}
+
+// lock logs out and forgets the client, once running invocations are done
+func (s *server) lock() {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	s.forget()
+}
+
+// forget logs out and forgets the client, s.runMu must be held
+func (s *server) forget() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+		s.idleTimer = nil
+	}
+	if s.client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		defer cancel()
+		s.client.Logout(ctx)
+		s.client = nil
+		s.logf("Agent Locked\n")
+	}
+}
+
+// unlock logs in with the passphrase, prompts for MFA are sent to the CLI
+func (s *server) unlock(ctx context.Context, passphrase string, st *stream) error {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("timeout"))
+	defer cancel()
+
+	_, err := s.captured(ctx, st, func(ctx context.Context) (util.AgentResponse, error) {
+		client, err := util.LoginClient(ctx, passphrase)
+		if err != nil {
+			return util.AgentResponse{}, err
+		}
+		s.forget()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.client = client
+		if s.idleTimeout > 0 {
+			s.idleTimer = time.AfterFunc(s.idleTimeout, s.lock)
+		}
+		return util.AgentResponse{}, nil
+	})
+	s.restoreConfig()
+	if err != nil {
+		return err
+	}
+	s.logf("Agent Unlocked\n")
+	return nil
+}
@@ -0,0 +1,247 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/viper"
+)
+
+func TestServerAnswer(t *testing.T) {
+	s := &server{keyID: "key", server: "https://passbolt.example.com", log: &bytes.Buffer{}}
+	st := newStream(&bytes.Buffer{})
+	ctx := context.Background()
+
+	if res := s.answer(ctx, util.AgentRequest{Command: "status"}, st); !res.Locked {
+		t.Errorf("locked status = %+v", res)
+	}
+	for _, command := range []string{"run", "resolve", "resource"} {
+		req := util.AgentRequest{Command: command, KeyID: "key", Server: "https://passbolt.example.com"}
+		if res := s.answer(ctx, req, st); !res.Locked {
+			t.Errorf("locked %v = %+v", command, res)
+		}
+	}
+	if res := s.answer(ctx, util.AgentRequest{Command: "unlock"}, st); res.Error == "" || !res.Locked {
+		t.Errorf("unlock without passphrase = %+v", res)
+	}
+	if res := s.answer(ctx, util.AgentRequest{Command: "dump"}, st); res.Error == "" {
+		t.Errorf("unknown command = %+v", res)
+	}
+
+	s.client = &api.Client{}
+	if res := s.answer(ctx, util.AgentRequest{Command: "status"}, st); res.Locked {
+		t.Errorf("unlocked status = %+v", res)
+	}
+	if res := s.answer(ctx, util.AgentRequest{Command: "status", KeyID: "other"}, st); !res.Locked {
+		t.Errorf("status for other key = %+v", res)
+	}
+	req := util.AgentRequest{Command: "run", KeyID: "other", Server: "https://passbolt.example.com"}
+	if res := s.answer(ctx, req, st); !res.Locked {
+		t.Errorf("run for other key = %+v", res)
+	}
+	req = util.AgentRequest{Command: "run", KeyID: "key", Server: "https://other.example.com"}
+	if res := s.answer(ctx, req, st); !res.Locked {
+		t.Errorf("run for other server = %+v", res)
+	}
+}
+
+func TestServerHandle(t *testing.T) {
+	if !peerCredentialsSupported {
+		t.Skip("peer credentials are not supported")
+	}
+	path := filepath.Join(t.TempDir(), "agent.sock")
+	viper.Set("agentSocket", path)
+	defer viper.Set("agentSocket", "")
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("socket = %v, %v", info, err)
+	}
+
+	s := &server{keyID: util.AgentKeyID("key"), uid: os.Getuid(), idleTimeout: 5, log: &bytes.Buffer{}}
+	go func() {
+		conn, err := listener.AcceptUnix()
+		if err == nil {
+			s.handle(conn)
+		}
+	}()
+
+	res, err := util.CallAgent(util.AgentRequest{Command: "status"})
+	if err != nil || !res.Locked || res.IdleTimeout != 5 {
+		t.Errorf("status = %+v, %v", res, err)
+	}
+}
+
+func TestCheckSocketDir(t *testing.T) {
+	if !peerCredentialsSupported {
+		t.Skip("the agent is not supported")
+	}
+	dir := t.TempDir()
+
+	private := filepath.Join(dir, "private")
+	if err := prepareSocketDir(private); err != nil {
+		t.Errorf("new directory: %v", err)
+	}
+
+	shared := filepath.Join(dir, "shared")
+	os.Mkdir(shared, 0700)
+	os.Chmod(shared, 0755)
+	if err := prepareSocketDir(shared); err == nil {
+		t.Error("accepted a directory with permissions 0755")
+	}
+
+	link := filepath.Join(dir, "link")
+	os.Symlink(private, link)
+	if err := prepareSocketDir(link); err == nil {
+		t.Error("accepted a symlink")
+	}
+}
+
+func TestForwarder(t *testing.T) {
+	var mu sync.Mutex
+	var sent bytes.Buffer
+	f, err := newForwarder(func(data []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent.Write(data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Output containing part of the marker must not be held back forever
+	f.w.Write([]byte("before"))
+	f.w.Write(f.marker[:4])
+	f.flush()
+	mu.Lock()
+	if got := sent.String(); got != "before"+string(f.marker[:4]) {
+		t.Errorf("sent before flush = %q", got)
+	}
+	mu.Unlock()
+
+	f.w.Write([]byte("after"))
+	f.close()
+	if got := sent.String(); got != "before"+string(f.marker[:4])+"after" {
+		t.Errorf("sent = %q", got)
+	}
+}
+
+func TestMarkerPrefixLen(t *testing.T) {
+	marker := []byte("marker")
+	tests := []struct {
+		data string
+		want int
+	}{
+		{"output", 0},
+		{"output m", 1},
+		{"output mark", 4},
+		{"mark", 4},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := markerPrefixLen([]byte(tt.data), marker); got != tt.want {
+			t.Errorf("markerPrefixLen(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestCapturePrompt(t *testing.T) {
+	agentConn, cliConn := net.Pipe()
+	defer agentConn.Close()
+	defer cliConn.Close()
+
+	st := newStream(agentConn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go st.receive(ctx, cancel)
+	c, err := captureOutput(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer := make(chan string)
+	go func() {
+		os.Stdout.WriteString("Deleting 3 Resources\n")
+		input, err := c.prompt("Continue? ", false)
+		if err != nil {
+			input = err.Error()
+		}
+		answer <- input
+	}()
+
+	// The output written before the prompt arrives first
+	cli := newStream(cliConn)
+	var res util.AgentResponse
+	if err := cli.dec.Decode(&res); err != nil || string(res.Stdout) != "Deleting 3 Resources\n" {
+		t.Fatalf("first response = %+v, %v", res, err)
+	}
+	res = util.AgentResponse{}
+	if err := cli.dec.Decode(&res); err != nil || res.Prompt != "Continue? " || res.Secret {
+		t.Fatalf("second response = %+v, %v", res, err)
+	}
+	cli.enc.Encode(util.AgentRequest{Input: "y"})
+	if got := <-answer; got != "y" {
+		t.Errorf("answer = %q", got)
+	}
+	c.restore()
+}
+
+func TestServeStopsWhenCLIDisconnects(t *testing.T) {
+	agentConn, cliConn := net.Pipe()
+	defer agentConn.Close()
+
+	started := make(chan struct{})
+	Execute = func(ctx context.Context, args []string) int {
+		close(started)
+		<-ctx.Done()
+		return 130
+	}
+	defer func() { Execute = nil }()
+
+	s := &server{keyID: "key", server: "https://passbolt.example.com", client: &api.Client{}, log: &bytes.Buffer{}}
+	st := newStream(agentConn)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go st.receive(ctx, cancel)
+
+	done := make(chan util.AgentResponse)
+	go func() {
+		done <- s.answer(ctx, util.AgentRequest{
+			Command: "run",
+			KeyID:   "key",
+			Server:  "https://passbolt.example.com",
+			Args:    []string{"get", "totp", "--id", "x"},
+			Dir:     t.TempDir(),
+			Env:     os.Environ(),
+		}, st)
+	}()
+	<-started
+	cliConn.Close()
+
+	select {
+	case res := <-done:
+		if res.ExitCode != 130 {
+			t.Errorf("response = %+v", res)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the invocation kept running after the CLI disconnected")
+	}
+
+	// The agent is not blocked by the invocation anymore
+	s.client = nil
+	if res := s.answer(context.Background(), util.AgentRequest{Command: "lock"}, st); !res.Locked {
+		t.Errorf("lock = %+v", res)
+	}
+}
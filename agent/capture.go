@@ -0,0 +1,256 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/pterm/pterm"
+)
+
+// stream is the connection to a CLI, responses may be sent concurrently
+type stream struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	dec *json.Decoder
+	// input receives the answers to prompts, it is closed when the CLI disconnects
+	input chan util.AgentRequest
+}
+
+func newStream(conn io.ReadWriter) *stream {
+	return &stream{
+		enc:   json.NewEncoder(conn),
+		dec:   json.NewDecoder(conn),
+		input: make(chan util.AgentRequest),
+	}
+}
+
+// receive reads the answers of the CLI after its request and calls cancel
+// once it disconnects
+func (st *stream) receive(ctx context.Context, cancel context.CancelFunc) {
+	defer cancel()
+	defer close(st.input)
+	for {
+		var req util.AgentRequest
+		err := st.dec.Decode(&req)
+		if err != nil {
+			return
+		}
+		select {
+		case st.input <- req:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (st *stream) send(res util.AgentResponse) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.enc.Encode(res)
+}
+
+// capture sends what is written to stdout and stderr to a CLI while the agent
+// handles its request
+type capture struct {
+	st     *stream
+	stdout *forwarder
+	stderr *forwarder
+	stdin  *os.File
+
+	oldStdout, oldStderr, oldStdin *os.File
+	oldProgressbar                 io.Writer
+
+	promptMu sync.Mutex
+}
+
+// captureOutput replaces stdout and stderr with pipes forwarded to st, and
+// stdin with an empty one, until restore is called
+func captureOutput(st *stream) (*capture, error) {
+	stdout, err := newForwarder(func(data []byte) { st.send(util.AgentResponse{Stdout: data}) })
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := newForwarder(func(data []byte) { st.send(util.AgentResponse{Stderr: data}) })
+	if err != nil {
+		stdout.close()
+		return nil, err
+	}
+	stdin, stdinW, err := os.Pipe()
+	if err != nil {
+		stdout.close()
+		stderr.close()
+		return nil, fmt.Errorf("creating Pipe: %w", err)
+	}
+	stdinW.Close()
+
+	c := &capture{
+		st:             st,
+		stdout:         stdout,
+		stderr:         stderr,
+		stdin:          stdin,
+		oldStdout:      os.Stdout,
+		oldStderr:      os.Stderr,
+		oldStdin:       os.Stdin,
+		oldProgressbar: pterm.DefaultProgressbar.Writer,
+	}
+	os.Stdout, os.Stderr, os.Stdin = stdout.w, stderr.w, stdin
+	pterm.SetDefaultOutput(stdout.w)
+	pterm.DefaultProgressbar.Writer = stderr.w
+	return c, nil
+}
+
+// restore puts back stdout, stderr and stdin once all output is forwarded
+func (c *capture) restore() {
+	os.Stdout, os.Stderr, os.Stdin = c.oldStdout, c.oldStderr, c.oldStdin
+	pterm.SetDefaultOutput(c.oldStdout)
+	pterm.DefaultProgressbar.Writer = c.oldProgressbar
+	c.stdout.close()
+	c.stderr.close()
+	c.stdin.Close()
+}
+
+// prompt asks the CLI for input, after everything written so far has reached it
+func (c *capture) prompt(prompt string, secret bool) (string, error) {
+	c.promptMu.Lock()
+	defer c.promptMu.Unlock()
+
+	c.stdout.flush()
+	c.stderr.flush()
+	err := c.st.send(util.AgentResponse{Prompt: prompt, Secret: secret})
+	if err != nil {
+		return "", fmt.Errorf("sending Prompt: %w", err)
+	}
+	answer, ok := <-c.st.input
+	if !ok {
+		return "", errors.New("reading Input: the CLI disconnected")
+	}
+	if answer.Error != "" {
+		return "", errors.New(answer.Error)
+	}
+	return answer.Input, nil
+}
+
+// forwarder reads a pipe and sends what is written to it
+type forwarder struct {
+	r, w *os.File
+	send func(data []byte)
+	// marker is written by flush, once the reader sees it everything
+	// written before has been sent
+	marker  []byte
+	flushed chan struct{}
+	done    chan struct{}
+}
+
+func newForwarder(send func(data []byte)) (*forwarder, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("creating Pipe: %w", err)
+	}
+	marker := make([]byte, 16)
+	rand.Read(marker)
+	f := &forwarder{
+		r:       r,
+		w:       w,
+		send:    send,
+		marker:  marker,
+		flushed: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go f.run()
+	return f, nil
+}
+
+func (f *forwarder) run() {
+	defer close(f.done)
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, err := f.r.Read(buf)
+		pending = append(pending, buf[:n]...)
+		for {
+			i := bytes.Index(pending, f.marker)
+			if i < 0 {
+				break
+			}
+			if i > 0 {
+				f.send(bytes.Clone(pending[:i]))
+			}
+			pending = pending[i+len(f.marker):]
+			f.flushed <- struct{}{}
+		}
+
+		// Hold back what could be the start of a marker
+		keep := 0
+		if err == nil {
+			keep = markerPrefixLen(pending, f.marker)
+		}
+		if len(pending) > keep {
+			f.send(bytes.Clone(pending[:len(pending)-keep]))
+			pending = bytes.Clone(pending[len(pending)-keep:])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// markerPrefixLen returns the length of the longest end of data that is the start of marker
+func markerPrefixLen(data, marker []byte) int {
+	for n := min(len(data), len(marker)-1); n > 0; n-- {
+		if bytes.HasSuffix(data, marker[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// flush waits until everything written so far has been sent
+func (f *forwarder) flush() {
+	f.w.Write(f.marker)
+	<-f.flushed
+}
+
+// close sends the remaining output and closes the pipe
+func (f *forwarder) close() {
+	f.w.Close()
+	<-f.done
+	f.r.Close()
+}
+
+// useEnvironment switches to the environment and working directory of the CLI
+// until restore is called
+func useEnvironment(env []string, dir string) (restore func(), err error) {
+	oldDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting Working Directory: %w", err)
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("changing to Working Directory: %w", err)
+	}
+	oldEnv := os.Environ()
+	setEnv(env)
+	return func() {
+		os.Chdir(oldDir)
+		setEnv(oldEnv)
+	}, nil
+}
+
+func setEnv(env []string) {
+	os.Clearenv()
+	for _, kv := range env {
+		k, v, ok := strings.Cut(kv, "=")
+		if ok {
+			os.Setenv(k, v)
+		}
+	}
+}
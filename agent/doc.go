@@ -0,0 +1,2 @@
+// Package agent implements a local agent that keeps the unlocked private key and a logged in client in memory and runs invocations of the CLI with them.
+package agent
@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AgentLockCmd Locks the Agent
+var AgentLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Makes the Agent log out and forget the unlocked Private Key",
+	Args:  cobra.NoArgs,
+	RunE:  AgentLock,
+}
+
+// AgentUnlockCmd Unlocks the Agent
+var AgentUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Makes the Agent unlock the Private Key and log in",
+	Args:  cobra.NoArgs,
+	RunE:  AgentUnlock,
+}
+
+// AgentStatusCmd Shows if the Agent is locked
+var AgentStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows if the Agent is running and locked",
+	Args:  cobra.NoArgs,
+	RunE:  AgentStatus,
+}
+
+func AgentLock(cmd *cobra.Command, args []string) error {
+	_, err := util.CallAgent(util.AgentRequest{Command: "lock"})
+	if err != nil {
+		return err
+	}
+	fmt.Println("Agent Locked")
+	return nil
+}
+
+func AgentUnlock(cmd *cobra.Command, args []string) error {
+	passphrase := viper.GetString("userPassword")
+	if passphrase == "" {
+		pw, err := util.ReadPassword("Enter Password:")
+		if err != nil {
+			fmt.Println()
+			return fmt.Errorf("reading Password: %w", err)
+		}
+		passphrase = pw
+		fmt.Println()
+	}
+	cmd.SilenceUsage = true
+
+	_, err := util.CallAgent(util.AgentRequest{Command: "unlock", Passphrase: passphrase})
+	if err != nil {
+		return err
+	}
+	fmt.Println("Agent Unlocked")
+	return nil
+}
+
+func AgentStatus(cmd *cobra.Command, args []string) error {
+	res, err := util.CallAgent(util.AgentRequest{Command: "status"})
+	if err != nil {
+		return err
+	}
+	if res.Locked {
+		fmt.Println("Agent is locked")
+	} else if res.IdleTimeout > 0 {
+		fmt.Printf("Agent is unlocked, it locks after being idle for %v\n", res.IdleTimeout)
+	} else {
+		fmt.Println("Agent is unlocked")
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+)
+
+const peerCredentialsSupported = true
+
+// peerUID returns the uid of the process on the other end of conn
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	err = raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, fmt.Errorf("getting Peer Credentials: %w", credErr)
+	}
+	return int(cred.Uid), nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// Without peer credentials any local user able to reach the socket could use
+// the logged in client, so the agent refuses to start
+const peerCredentialsSupported = false
+
+func peerUID(conn *net.UnixConn) (int, error) {
+	return 0, fmt.Errorf("peer credentials are not supported on %v", runtime.GOOS)
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin
+
+package agent
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+func prepareSocketDir(dir string) error {
+	return fmt.Errorf("the Agent is not supported on %v", runtime.GOOS)
+}
+
+func listenPrivate(path string) (*net.UnixListener, error) {
+	return nil, fmt.Errorf("the Agent is not supported on %v", runtime.GOOS)
+}
@@ -0,0 +1,53 @@
+//go:build linux || darwin
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// prepareSocketDir creates the directory of the socket and checks that it is
+// owned by and only accessible to the current user
+func prepareSocketDir(dir string) error {
+	err := os.MkdirAll(filepath.Dir(dir), 0700)
+	if err != nil {
+		return fmt.Errorf("creating Socket Directory: %w", err)
+	}
+	err = os.Mkdir(dir, 0700)
+	if err != nil && !errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("creating Socket Directory: %w", err)
+	}
+	return checkSocketDir(dir)
+}
+
+func checkSocketDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return fmt.Errorf("checking Socket Directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("refusing to use Socket Directory %v: not a Directory", dir)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("refusing to use Socket Directory %v: not owned by the current User", dir)
+	}
+	if info.Mode().Perm() != 0700 {
+		return fmt.Errorf("refusing to use Socket Directory %v: Permissions are %#o instead of 0700", dir, info.Mode().Perm())
+	}
+	return nil
+}
+
+// listenPrivate creates the socket with a umask, so it never has broader
+// permissions than 0600
+func listenPrivate(path string) (*net.UnixListener, error) {
+	old := syscall.Umask(0177)
+	defer syscall.Umask(old)
+	return net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+}
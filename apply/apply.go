@@ -0,0 +1,134 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+)
+
+// ApplyCmd Converges Passbolt to a Manifest
+var ApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Converges Passbolt to a Manifest",
+	Long: `Reads a YAML Manifest describing Groups, Folders and Resources, compares it with the Server
+and creates, updates or deletes what differs. Secrets are referenced from environment variables or
+files, so the Manifest can be kept in version control:
+
+  groups:
+    - name: Ops
+      managers: [ada@passbolt.com]
+      members: [betty@passbolt.com]
+  folders:
+    - path: Infra/Databases
+      permissions:
+        - group: Ops
+          type: update
+  resources:
+    - name: postgres
+      folder: Infra/Databases
+      username: admin
+      uri: postgres://db.example.com
+      password:
+        env: POSTGRES_PASSWORD    # or file: ./secrets/postgres
+      expiry: 2030-01-01T00:00:00Z
+      permissions:
+        - user: betty@passbolt.com
+          type: read
+    - name: old-db
+      folder: Infra/Databases
+      state: absent
+
+Resources are identified by Folder path and name, Folders by path and Groups by name.
+Fields that are omitted are left unchanged, and Group memberships are replaced by the declared ones,
+except that your own membership is kept if you are not declared.
+With --prune, undeclared Resources in declared Folders and undeclared Permissions are removed.
+Use --dry-run to only print the plan.`,
+	Aliases: []string{},
+	RunE:    Apply,
+}
+
+func init() {
+	ApplyCmd.Flags().StringP("file", "f", "", "Manifest File")
+	ApplyCmd.Flags().Bool("prune", false, "Delete undeclared Resources in declared Folders and remove undeclared Permissions")
+
+	ApplyCmd.MarkFlagRequired("file")
+}
+
+func Apply(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	prune, err := cmd.Flags().GetBool("prune")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("reading Manifest: %w", err)
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return err
+	}
+
+	needSecrets := false
+	for _, r := range m.Resources {
+		if r.Password != nil {
+			needSecrets = true
+		}
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	state, err := getServerState(ctx, client, needSecrets)
+	if err != nil {
+		return err
+	}
+
+	p := planner{
+		client:  client,
+		state:   state,
+		baseDir: filepath.Dir(filename),
+		prune:   prune,
+		isV5:    util.DefaultResourceTypeIsV5(client),
+	}
+	err = p.plan(ctx, m)
+	if err != nil {
+		return err
+	}
+
+	if len(p.changes) == 0 {
+		fmt.Println("No Changes")
+		return nil
+	}
+
+	for i, c := range p.changes {
+		if util.DryRun() {
+			util.PrintDryRun(c.method, c.path, "%v", c.description)
+			continue
+		}
+		fmt.Printf("[%v/%v] %v\n", i+1, len(p.changes), c.description)
+		err = c.run(ctx)
+		if err != nil {
+			return fmt.Errorf("applying %v: %w", c.description, err)
+		}
+	}
+
+	if !util.DryRun() {
+		fmt.Printf("Applied %v Changes\n", len(p.changes))
+	}
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package apply implements converging Passbolt to a declarative YAML manifest.
+package apply
@@ -0,0 +1,215 @@
+package apply
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// States an entry of the manifest can declare
+const (
+	statePresent = "present"
+	stateAbsent  = "absent"
+)
+
+// manifest is the declarative description of a vault read by apply
+type manifest struct {
+	Groups    []manifestGroup    `yaml:"groups"`
+	Folders   []manifestFolder   `yaml:"folders"`
+	Resources []manifestResource `yaml:"resources"`
+}
+
+type manifestGroup struct {
+	Name     string   `yaml:"name"`
+	State    string   `yaml:"state"`
+	Managers []string `yaml:"managers"`
+	Members  []string `yaml:"members"`
+}
+
+type manifestFolder struct {
+	Path        string               `yaml:"path"`
+	State       string               `yaml:"state"`
+	Permissions []manifestPermission `yaml:"permissions"`
+}
+
+type manifestResource struct {
+	Name        string               `yaml:"name"`
+	Folder      string               `yaml:"folder"`
+	State       string               `yaml:"state"`
+	Type        string               `yaml:"type"`
+	Username    string               `yaml:"username"`
+	URI         string               `yaml:"uri"`
+	Description string               `yaml:"description"`
+	Password    *manifestSecret      `yaml:"password"`
+	Expiry      string               `yaml:"expiry"`
+	Permissions []manifestPermission `yaml:"permissions"`
+}
+
+// manifestSecret references a secret value, so the manifest itself can be
+// kept in git without secrets
+type manifestSecret struct {
+	Env  string `yaml:"env"`
+	File string `yaml:"file"`
+}
+
+// manifestPermission grants a User (by username) or a Group (by name) access
+type manifestPermission struct {
+	User  string `yaml:"user"`
+	Group string `yaml:"group"`
+	Type  string `yaml:"type"`
+}
+
+// permissionTypes maps the names accepted in a manifest to Permission Types
+var permissionTypes = map[string]int{
+	"read":   1,
+	"update": 7,
+	"owner":  15,
+}
+
+// parseManifest decodes and validates a manifest, unknown keys are rejected
+// to catch typos.
+func parseManifest(data []byte) (manifest, error) {
+	var m manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err := decoder.Decode(&m)
+	if err != nil {
+		return manifest{}, fmt.Errorf("parsing Manifest: %w", err)
+	}
+
+	groups := map[string]bool{}
+	for i, g := range m.Groups {
+		if g.Name == "" {
+			return manifest{}, fmt.Errorf("group %v has no name", i+1)
+		}
+		if groups[g.Name] {
+			return manifest{}, fmt.Errorf("group %q is declared twice", g.Name)
+		}
+		groups[g.Name] = true
+		if err := validateState(g.State); err != nil {
+			return manifest{}, fmt.Errorf("group %q: %w", g.Name, err)
+		}
+		// Undeclared members are removed, so an empty list would empty the Group
+		if g.State != stateAbsent && len(g.Managers) == 0 && len(g.Members) == 0 {
+			return manifest{}, fmt.Errorf("group %q: declare its managers or members, all other members are removed", g.Name)
+		}
+	}
+
+	folders := map[string]bool{}
+	for i, f := range m.Folders {
+		m.Folders[i].Path = cleanPath(f.Path)
+		f = m.Folders[i]
+		if f.Path == "" {
+			return manifest{}, fmt.Errorf("folder %v has no path", i+1)
+		}
+		if folders[f.Path] {
+			return manifest{}, fmt.Errorf("folder %q is declared twice", f.Path)
+		}
+		folders[f.Path] = true
+		if err := validateState(f.State); err != nil {
+			return manifest{}, fmt.Errorf("folder %q: %w", f.Path, err)
+		}
+		if err := validatePermissions(f.Permissions); err != nil {
+			return manifest{}, fmt.Errorf("folder %q: %w", f.Path, err)
+		}
+	}
+
+	resources := map[string]bool{}
+	for i, r := range m.Resources {
+		m.Resources[i].Folder = cleanPath(r.Folder)
+		r = m.Resources[i]
+		if r.Name == "" {
+			return manifest{}, fmt.Errorf("resource %v has no name", i+1)
+		}
+		key := resourceKey(r.Folder, r.Name)
+		if resources[key] {
+			return manifest{}, fmt.Errorf("resource %q is declared twice", key)
+		}
+		resources[key] = true
+		if err := validateState(r.State); err != nil {
+			return manifest{}, fmt.Errorf("resource %q: %w", key, err)
+		}
+		if err := validatePermissions(r.Permissions); err != nil {
+			return manifest{}, fmt.Errorf("resource %q: %w", key, err)
+		}
+		if r.Password != nil && (r.Password.Env == "") == (r.Password.File == "") {
+			return manifest{}, fmt.Errorf("resource %q: password needs exactly one of env or file", key)
+		}
+		if r.Expiry != "" && r.Expiry != "none" {
+			// Durations are relative to now and would never converge
+			if _, err := time.Parse(time.RFC3339, r.Expiry); err != nil {
+				return manifest{}, fmt.Errorf("resource %q: expiry must be an RFC3339 timestamp or none: %w", key, err)
+			}
+		}
+	}
+	return m, nil
+}
+
+func validateState(state string) error {
+	switch state {
+	case "", statePresent, stateAbsent:
+		return nil
+	}
+	return fmt.Errorf("invalid state %q, must be %v or %v", state, statePresent, stateAbsent)
+}
+
+func validatePermissions(permissions []manifestPermission) error {
+	for _, p := range permissions {
+		if (p.User == "") == (p.Group == "") {
+			return fmt.Errorf("a permission needs exactly one of user or group")
+		}
+		if _, ok := permissionTypes[p.Type]; !ok {
+			return fmt.Errorf("invalid permission type %q, must be read, update or owner", p.Type)
+		}
+	}
+	return nil
+}
+
+// cleanPath normalizes a slash separated Folder path
+func cleanPath(path string) string {
+	return strings.Join(splitPath(path), "/")
+}
+
+func splitPath(path string) []string {
+	parts := []string{}
+	for _, p := range strings.Split(path, "/") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// resourceKey identifies a Resource by its Folder path and name
+func resourceKey(folder, name string) string {
+	if folder == "" {
+		return name
+	}
+	return folder + "/" + name
+}
+
+// resolve reads the referenced secret, relative files are resolved against
+// the directory of the manifest.
+func (s manifestSecret) resolve(baseDir string) (string, error) {
+	if s.Env != "" {
+		value, ok := os.LookupEnv(s.Env)
+		if !ok {
+			return "", fmt.Errorf("environment variable %v is not set", s.Env)
+		}
+		return value, nil
+	}
+	path := s.File
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
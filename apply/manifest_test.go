@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseManifest(t *testing.T) {
+	m, err := parseManifest([]byte(`
+groups:
+  - name: Ops
+    managers: [ada@passbolt.com]
+folders:
+  - path: /Infra//Databases/
+    permissions:
+      - group: Ops
+        type: update
+resources:
+  - name: postgres
+    folder: Infra/Databases
+    password:
+      env: PG_PASSWORD
+    expiry: 2030-01-01T00:00:00Z
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Folders[0].Path != "Infra/Databases" {
+		t.Errorf("folder path not cleaned: %q", m.Folders[0].Path)
+	}
+	if m.Resources[0].Password.Env != "PG_PASSWORD" || m.Groups[0].Managers[0] != "ada@passbolt.com" {
+		t.Errorf("manifest = %+v", m)
+	}
+}
+
+func TestParseManifest_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown key":         "resources:\n  - name: a\n    pasword: {env: X}\n",
+		"duplicate resource":  "resources:\n  - name: a\n  - name: a\n",
+		"two secret sources":  "resources:\n  - name: a\n    password: {env: X, file: y}\n",
+		"no secret source":    "resources:\n  - name: a\n    password: {}\n",
+		"plaintext secret":    "resources:\n  - name: a\n    password: {value: y}\n",
+		"empty group":         "groups:\n  - name: a\n",
+		"relative expiry":     "resources:\n  - name: a\n    expiry: 7d\n",
+		"bad state":           "folders:\n  - path: a\n    state: gone\n",
+		"bad permission type": "folders:\n  - path: a\n    permissions: [{user: a, type: admin}]\n",
+		"user and group":      "folders:\n  - path: a\n    permissions: [{user: a, group: b, type: read}]\n",
+	}
+	for name, data := range cases {
+		if _, err := parseManifest([]byte(data)); err == nil {
+			t.Errorf("%v: expected error", name)
+		}
+	}
+}
+
+func TestManifestSecret_Resolve(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pw"), []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APPLY_TEST_SECRET", "from-env")
+
+	for secret, want := range map[manifestSecret]string{
+		{File: "pw"}:               "from-file",
+		{Env: "APPLY_TEST_SECRET"}: "from-env",
+		{File: dir + "/pw"}:        "from-file",
+	} {
+		got, err := secret.resolve(dir)
+		if err != nil || got != want {
+			t.Errorf("resolve(%+v) = %q, %v, want %q", secret, got, err, want)
+		}
+	}
+
+	_, err := manifestSecret{Env: "APPLY_TEST_UNSET"}.resolve(dir)
+	if err == nil || !strings.Contains(err.Error(), "not set") {
+		t.Errorf("unset env err = %v", err)
+	}
+}
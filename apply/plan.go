@@ -0,0 +1,569 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+// change is a single step of a plan, printed before it is run
+type change struct {
+	method      string
+	path        string
+	description string
+	run         func(ctx context.Context) error
+}
+
+// serverState is what the server currently holds, as far as apply cares.
+// groupIDs and folderIDs are filled in as the plan creates new ones.
+type serverState struct {
+	self      string
+	userIDs   map[string]string
+	groupIDs  map[string]string
+	folderIDs map[string]string
+	folders   map[string]api.Folder
+	resources map[string]resource.DecryptedResource
+	// Paths and keys that exist more than once and can't be managed
+	ambiguous map[string]bool
+	// Resource keys by Folder path, used for pruning
+	resourcesByFolder map[string][]string
+}
+
+// planner turns a manifest into a list of changes against a serverState
+type planner struct {
+	client  *api.Client
+	state   *serverState
+	baseDir string
+	prune   bool
+	isV5    bool
+
+	changes       []change
+	plannedGroups map[string]bool
+}
+
+func getServerState(ctx context.Context, client *api.Client, needSecrets bool) (*serverState, error) {
+	state := &serverState{
+		self:              util.CurrentUserID(client),
+		userIDs:           map[string]string{},
+		groupIDs:          map[string]string{},
+		folderIDs:         map[string]string{},
+		folders:           map[string]api.Folder{},
+		resources:         map[string]resource.DecryptedResource{},
+		ambiguous:         map[string]bool{},
+		resourcesByFolder: map[string][]string{},
+	}
+
+	users, err := client.GetUsers(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing User: %w", err)
+	}
+	for _, u := range users {
+		state.userIDs[userKey(u.Username)] = u.ID
+	}
+
+	groups, err := client.GetGroups(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing Group: %w", err)
+	}
+	for _, g := range groups {
+		state.groupIDs[g.Name] = g.ID
+	}
+
+	folders, err := client.GetFolders(ctx, &api.GetFoldersOptions{
+		ContainPermissions: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing Folder: %w", err)
+	}
+	folderPaths := util.FolderPaths(folders)
+	for _, f := range folders {
+		path := strings.Join(folderPaths[f.ID], "/")
+		if _, ok := state.folderIDs[path]; ok {
+			state.ambiguous["folder "+path] = true
+		}
+		state.folderIDs[path] = f.ID
+		state.folders[path] = f
+	}
+
+	resources, err := resource.GetDecryptedResources(ctx, client, &api.GetResourcesOptions{
+		ContainSecret:      needSecrets,
+		ContainPermissions: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		folder := strings.Join(folderPaths[r.Resource.FolderParentID], "/")
+		key := resourceKey(folder, helper.GetStringField(r.Metadata, "name"))
+		if _, ok := state.resources[key]; ok {
+			state.ambiguous["resource "+key] = true
+		}
+		state.resources[key] = r
+		state.resourcesByFolder[folder] = append(state.resourcesByFolder[folder], key)
+	}
+	return state, nil
+}
+
+func (p *planner) add(method, path, description string, run func(ctx context.Context) error) {
+	p.changes = append(p.changes, change{method: method, path: path, description: description, run: run})
+}
+
+// plan computes all changes needed to converge the server to m
+func (p *planner) plan(ctx context.Context, m manifest) error {
+	p.plannedGroups = map[string]bool{}
+
+	for _, g := range m.Groups {
+		if err := p.planGroup(ctx, g); err != nil {
+			return err
+		}
+	}
+	if err := p.planFolders(m); err != nil {
+		return err
+	}
+	for _, r := range m.Resources {
+		if err := p.planResource(r); err != nil {
+			return err
+		}
+	}
+	if p.prune {
+		p.planPrune(m)
+	}
+	p.planFolderDeletes(m)
+	return nil
+}
+
+func (p *planner) planGroup(ctx context.Context, g manifestGroup) error {
+	id, exists := p.state.groupIDs[g.Name]
+	if g.State == stateAbsent {
+		if exists {
+			p.add("DELETE", fmt.Sprintf("/groups/%s.json", id), fmt.Sprintf("delete Group %q", g.Name), func(ctx context.Context) error {
+				return p.client.DeleteGroup(ctx, id)
+			})
+		}
+		return nil
+	}
+
+	var current []helper.GroupMembership
+	if exists {
+		var err error
+		_, current, err = helper.GetGroup(ctx, p.client, id)
+		if err != nil {
+			return fmt.Errorf("getting Group %v: %w", g.Name, err)
+		}
+	}
+	ops, err := membershipOperations(g, current, p.state.userIDs, p.state.self)
+	if err != nil {
+		return fmt.Errorf("group %q: %w", g.Name, err)
+	}
+
+	if !exists {
+		p.plannedGroups[g.Name] = true
+		p.add("POST", "/groups.json", fmt.Sprintf("create Group %q with %v Memberships", g.Name, len(ops)), func(ctx context.Context) error {
+			id, err := helper.CreateGroup(ctx, p.client, g.Name, ops)
+			if err != nil {
+				return err
+			}
+			p.state.groupIDs[g.Name] = id
+			return nil
+		})
+	} else if len(ops) != 0 {
+		p.add("PUT", fmt.Sprintf("/groups/%s.json", id), fmt.Sprintf("update %v Memberships of Group %q", len(ops), g.Name), func(ctx context.Context) error {
+			return helper.UpdateGroup(ctx, p.client, id, "", ops)
+		})
+	}
+	return nil
+}
+
+// planFolders creates all declared Folders, the Folders of declared
+// Resources and their parents, and converges the Permissions of declared
+// Folders.
+func (p *planner) planFolders(m manifest) error {
+	needed := map[string]bool{}
+	addWithParents := func(path string) {
+		parts := splitPath(path)
+		for i := range parts {
+			needed[strings.Join(parts[:i+1], "/")] = true
+		}
+	}
+	for _, f := range m.Folders {
+		if f.State != stateAbsent {
+			addWithParents(f.Path)
+		}
+	}
+	for _, r := range m.Resources {
+		if r.State != stateAbsent {
+			addWithParents(r.Folder)
+		}
+	}
+
+	paths := make([]string, 0, len(needed))
+	for path := range needed {
+		paths = append(paths, path)
+	}
+	sortByDepth(paths)
+
+	for _, path := range paths {
+		if p.state.ambiguous["folder "+path] {
+			return fmt.Errorf("folder %q exists more than once on the server", path)
+		}
+		if _, ok := p.state.folderIDs[path]; ok {
+			continue
+		}
+		parts := splitPath(path)
+		parent := strings.Join(parts[:len(parts)-1], "/")
+		name := parts[len(parts)-1]
+		p.add("POST", "/folders.json", fmt.Sprintf("create Folder %q", path), func(ctx context.Context) error {
+			id, err := helper.CreateFolder(ctx, p.client, p.state.folderIDs[parent], name)
+			if err != nil {
+				return err
+			}
+			p.state.folderIDs[path] = id
+			return nil
+		})
+	}
+
+	for _, f := range m.Folders {
+		if f.State == stateAbsent {
+			continue
+		}
+		current := p.state.folders[f.Path].Permissions
+		ops, err := permissionOperations(f.Permissions, current, p.planResolver(), p.state.self, p.prune)
+		if err != nil {
+			return fmt.Errorf("folder %q: %w", f.Path, err)
+		}
+		if len(ops) == 0 {
+			continue
+		}
+		path := f.Path
+		p.add("PUT", "/share/folder/"+p.idOrNew(p.state.folderIDs[path])+".json", fmt.Sprintf("change %v Permissions of Folder %q", len(ops), path), func(ctx context.Context) error {
+			ops, err := permissionOperations(f.Permissions, current, p.runResolver(), p.state.self, p.prune)
+			if err != nil {
+				return err
+			}
+			return helper.ShareFolder(ctx, p.client, p.state.folderIDs[path], ops)
+		})
+	}
+	return nil
+}
+
+func (p *planner) planResource(r manifestResource) error {
+	key := resourceKey(r.Folder, r.Name)
+	if p.state.ambiguous["resource "+key] {
+		return fmt.Errorf("resource %q exists more than once on the server", key)
+	}
+	current, exists := p.state.resources[key]
+	id := current.Resource.ID
+
+	if r.State == stateAbsent {
+		if exists {
+			p.add("DELETE", fmt.Sprintf("/resources/%s.json", id), fmt.Sprintf("delete Resource %q", key), func(ctx context.Context) error {
+				return p.client.DeleteResource(ctx, id)
+			})
+		}
+		return nil
+	}
+
+	var password *string
+	if r.Password != nil {
+		value, err := r.Password.resolve(p.baseDir)
+		if err != nil {
+			return fmt.Errorf("resource %q: %w", key, err)
+		}
+		password = &value
+	}
+
+	if !exists {
+		if password == nil {
+			return fmt.Errorf("resource %q does not exist and has no password to create it with", key)
+		}
+		slug := r.Type
+		if slug == "" {
+			if p.isV5 {
+				slug = "v5-default"
+			} else {
+				slug = "password-and-description"
+			}
+		}
+		metadata, secret := resourceUpdates(r, password, resource.DecryptedResource{})
+		metadata["name"] = r.Name
+
+		p.add("POST", "/resources.json", fmt.Sprintf("create Resource %q of type %v", key, slug), func(ctx context.Context) error {
+			id, err := helper.CreateResourceGeneric(ctx, p.client, slug, p.state.folderIDs[r.Folder], metadata, secret)
+			if err != nil {
+				return err
+			}
+			current.Resource.ID = id
+			return nil
+		})
+		if r.Expiry != "" && r.Expiry != "none" {
+			p.add("PUT", "/resources/<new>.json", fmt.Sprintf("set expiry of Resource %q to %v", key, r.Expiry), func(ctx context.Context) error {
+				return resource.SetResourceExpiry(ctx, p.client, current.Resource.ID, r.Expiry)
+			})
+		}
+		ops, err := permissionOperations(r.Permissions, nil, p.planResolver(), p.state.self, false)
+		if err != nil {
+			return fmt.Errorf("resource %q: %w", key, err)
+		}
+		if len(ops) != 0 {
+			p.add("PUT", "/share/resource/<new>.json", fmt.Sprintf("change %v Permissions of Resource %q", len(ops), key), func(ctx context.Context) error {
+				ops, err := permissionOperations(r.Permissions, nil, p.runResolver(), p.state.self, false)
+				if err != nil {
+					return err
+				}
+				return helper.ShareResource(ctx, p.client, current.Resource.ID, ops)
+			})
+		}
+		return nil
+	}
+
+	metadata, secret := resourceUpdates(r, password, current)
+	if len(metadata) != 0 || len(secret) != 0 {
+		p.add("PUT", fmt.Sprintf("/resources/%s.json", id), fmt.Sprintf("update Resource %q, metadata fields %v, secret fields %v", key, util.FieldNames(metadata), util.FieldNames(secret)), func(ctx context.Context) error {
+			return helper.UpdateResourceGeneric(ctx, p.client, id, metadata, secret)
+		})
+	}
+	if expiryChanged(r.Expiry, current.Resource.Expired) {
+		p.add("PUT", fmt.Sprintf("/resources/%s.json", id), fmt.Sprintf("set expiry of Resource %q to %v", key, r.Expiry), func(ctx context.Context) error {
+			return resource.SetResourceExpiry(ctx, p.client, id, r.Expiry)
+		})
+	}
+	ops, err := permissionOperations(r.Permissions, current.Resource.Permissions, p.planResolver(), p.state.self, p.prune)
+	if err != nil {
+		return fmt.Errorf("resource %q: %w", key, err)
+	}
+	if len(ops) != 0 {
+		p.add("PUT", fmt.Sprintf("/share/resource/%s.json", id), fmt.Sprintf("change %v Permissions of Resource %q", len(ops), key), func(ctx context.Context) error {
+			ops, err := permissionOperations(r.Permissions, current.Resource.Permissions, p.runResolver(), p.state.self, p.prune)
+			if err != nil {
+				return err
+			}
+			return helper.ShareResource(ctx, p.client, id, ops)
+		})
+	}
+	return nil
+}
+
+// planPrune deletes Resources in declared Folders that the manifest does
+// not declare
+func (p *planner) planPrune(m manifest) {
+	declared := map[string]bool{}
+	for _, r := range m.Resources {
+		declared[resourceKey(r.Folder, r.Name)] = true
+	}
+	for _, f := range m.Folders {
+		if f.State == stateAbsent {
+			continue
+		}
+		keys := append([]string{}, p.state.resourcesByFolder[f.Path]...)
+		sort.Strings(keys)
+		for _, key := range keys {
+			if declared[key] || p.state.ambiguous["resource "+key] {
+				continue
+			}
+			id := p.state.resources[key].Resource.ID
+			p.add("DELETE", fmt.Sprintf("/resources/%s.json", id), fmt.Sprintf("delete undeclared Resource %q", key), func(ctx context.Context) error {
+				return p.client.DeleteResource(ctx, id)
+			})
+		}
+	}
+}
+
+// planFolderDeletes deletes absent Folders, children first
+func (p *planner) planFolderDeletes(m manifest) {
+	paths := []string{}
+	for _, f := range m.Folders {
+		if _, ok := p.state.folderIDs[f.Path]; ok && f.State == stateAbsent {
+			paths = append(paths, f.Path)
+		}
+	}
+	sortByDepth(paths)
+	for i := len(paths) - 1; i >= 0; i-- {
+		path := paths[i]
+		id := p.state.folderIDs[path]
+		p.add("DELETE", fmt.Sprintf("/folders/%s.json", id), fmt.Sprintf("delete Folder %q", path), func(ctx context.Context) error {
+			return p.client.DeleteFolder(ctx, id)
+		})
+	}
+}
+
+// planResolver resolves Permission targets while planning, Groups that the
+// plan creates don't have an ID yet and get a placeholder
+func (p *planner) planResolver() aroResolver {
+	return func(perm manifestPermission) (string, string, error) {
+		if perm.Group != "" && p.plannedGroups[perm.Group] {
+			return "Group", "<new Group " + perm.Group + ">", nil
+		}
+		return p.runResolver()(perm)
+	}
+}
+
+// runResolver resolves Permission targets while running the plan
+func (p *planner) runResolver() aroResolver {
+	return func(perm manifestPermission) (string, string, error) {
+		if perm.User != "" {
+			id, ok := p.state.userIDs[userKey(perm.User)]
+			if !ok {
+				return "", "", fmt.Errorf("unknown User %q", perm.User)
+			}
+			return "User", id, nil
+		}
+		id, ok := p.state.groupIDs[perm.Group]
+		if !ok {
+			return "", "", fmt.Errorf("unknown Group %q", perm.Group)
+		}
+		return "Group", id, nil
+	}
+}
+
+func (p *planner) idOrNew(id string) string {
+	if id == "" {
+		return "<new>"
+	}
+	return id
+}
+
+// aroResolver returns the ARO type and ID of a Permission target
+type aroResolver func(perm manifestPermission) (string, string, error)
+
+// permissionOperations returns the share operations needed to go from the
+// current Permissions to the desired ones. Permissions that are not declared
+// are only removed when pruning, and never those of the current User.
+func permissionOperations(desired []manifestPermission, current []api.Permission, resolve aroResolver, self string, prune bool) ([]helper.ShareOperation, error) {
+	currentTypes := map[string]int{}
+	for _, c := range current {
+		currentTypes[c.AROForeignKey] = c.Type
+	}
+
+	ops := []helper.ShareOperation{}
+	declared := map[string]bool{}
+	for _, perm := range desired {
+		aro, id, err := resolve(perm)
+		if err != nil {
+			return nil, err
+		}
+		declared[id] = true
+		pType := permissionTypes[perm.Type]
+		if currentTypes[id] != pType {
+			ops = append(ops, helper.ShareOperation{Type: pType, ARO: aro, AROID: id})
+		}
+	}
+
+	if prune {
+		for _, c := range current {
+			if declared[c.AROForeignKey] || c.AROForeignKey == self {
+				continue
+			}
+			ops = append(ops, helper.ShareOperation{Type: -1, ARO: c.ARO, AROID: c.AROForeignKey})
+		}
+	}
+	return ops, nil
+}
+
+// membershipOperations returns the operations needed to make the members of
+// a Group match the manifest. Users listed as manager and member are managers.
+// Like for Permissions, the membership of the current User is kept if it is
+// not declared.
+func membershipOperations(desired manifestGroup, current []helper.GroupMembership, userIDs map[string]string, self string) ([]helper.GroupMembershipOperation, error) {
+	wantManager := map[string]bool{}
+	for _, u := range desired.Members {
+		wantManager[userKey(u)] = false
+	}
+	for _, u := range desired.Managers {
+		wantManager[userKey(u)] = true
+	}
+
+	ops := []helper.GroupMembershipOperation{}
+	isMember := map[string]bool{}
+	for _, c := range current {
+		isMember[userKey(c.Username)] = true
+		manager, ok := wantManager[userKey(c.Username)]
+		switch {
+		case !ok && c.UserID == self:
+		case !ok:
+			ops = append(ops, helper.GroupMembershipOperation{UserID: c.UserID, IsGroupManager: c.IsGroupManager, Delete: true})
+		case manager != c.IsGroupManager:
+			ops = append(ops, helper.GroupMembershipOperation{UserID: c.UserID, IsGroupManager: manager})
+		}
+	}
+
+	usernames := make([]string, 0, len(wantManager))
+	for u := range wantManager {
+		usernames = append(usernames, u)
+	}
+	sort.Strings(usernames)
+	for _, u := range usernames {
+		if isMember[u] {
+			continue
+		}
+		id, ok := userIDs[u]
+		if !ok {
+			return nil, fmt.Errorf("unknown User %q", u)
+		}
+		ops = append(ops, helper.GroupMembershipOperation{UserID: id, IsGroupManager: wantManager[u]})
+	}
+	return ops, nil
+}
+
+// userKey is the key of a User in serverState.userIDs, Usernames are matched
+// case-insensitively like util.Resolver does
+func userKey(username string) string {
+	return strings.ToLower(username)
+}
+
+// resourceUpdates returns the metadata and secret fields of r that differ
+// from current. Fields the manifest leaves empty are not managed.
+func resourceUpdates(r manifestResource, password *string, current resource.DecryptedResource) (map[string]any, map[string]any) {
+	metadata := map[string]any{}
+	secret := map[string]any{}
+
+	currentDescription := helper.GetStringField(current.Metadata, "description")
+	if currentDescription == "" {
+		currentDescription = helper.GetStringField(current.Secret, "description")
+	}
+	for field, value := range map[string][2]string{
+		"username":    {r.Username, helper.GetStringField(current.Metadata, "username")},
+		"uri":         {r.URI, helper.GetStringField(current.Metadata, "uri")},
+		"description": {r.Description, currentDescription},
+	} {
+		if value[0] != "" && value[0] != value[1] {
+			metadata[field] = value[0]
+		}
+	}
+	if password != nil && *password != helper.GetStringField(current.Secret, "password") {
+		secret["password"] = *password
+	}
+	return metadata, secret
+}
+
+// expiryChanged reports whether the declared expiry differs from the current
+func expiryChanged(expiry string, current *api.Time) bool {
+	switch expiry {
+	case "":
+		return false
+	case "none":
+		return current != nil
+	}
+	want, err := time.Parse(time.RFC3339, expiry)
+	if err != nil || current == nil {
+		return true
+	}
+	return !want.Equal(current.Time.Truncate(time.Second))
+}
+
+// sortByDepth sorts Folder paths so parents come before their children
+func sortByDepth(paths []string) {
+	sort.Slice(paths, func(i, j int) bool {
+		di, dj := strings.Count(paths[i], "/"), strings.Count(paths[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return paths[i] < paths[j]
+	})
+}
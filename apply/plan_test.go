@@ -0,0 +1,147 @@
+package apply
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+func testResolver(perm manifestPermission) (string, string, error) {
+	if perm.User != "" {
+		return "User", "u-" + perm.User, nil
+	}
+	if perm.Group == "missing" {
+		return "", "", fmt.Errorf("unknown Group")
+	}
+	return "Group", "g-" + perm.Group, nil
+}
+
+func TestPermissionOperations(t *testing.T) {
+	desired := []manifestPermission{
+		{User: "ada", Type: "read"},
+		{Group: "ops", Type: "owner"},
+	}
+	current := []api.Permission{
+		{ARO: "User", AROForeignKey: "u-ada", Type: 1},
+		{ARO: "User", AROForeignKey: "me", Type: 15},
+		{ARO: "User", AROForeignKey: "u-betty", Type: 7},
+	}
+
+	ops, err := permissionOperations(desired, current, testResolver, "me", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []helper.ShareOperation{{Type: 15, ARO: "Group", AROID: "g-ops"}}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("ops = %+v, want %+v", ops, want)
+	}
+
+	ops, err = permissionOperations(desired, current, testResolver, "me", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, helper.ShareOperation{Type: -1, ARO: "User", AROID: "u-betty"})
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("prune ops = %+v, want %+v (own permission must be kept)", ops, want)
+	}
+
+	if _, err := permissionOperations([]manifestPermission{{Group: "missing", Type: "read"}}, nil, testResolver, "me", false); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestMembershipOperations(t *testing.T) {
+	desired := manifestGroup{
+		Name:     "Ops",
+		Managers: []string{"Ada"},
+		Members:  []string{"betty", "Carol"},
+	}
+	current := []helper.GroupMembership{
+		{UserID: "u-ada", Username: "ada", IsGroupManager: false},
+		{UserID: "u-betty", Username: "betty"},
+		{UserID: "u-dave", Username: "dave"},
+		{UserID: "me", Username: "me", IsGroupManager: true},
+	}
+	ops, err := membershipOperations(desired, current, map[string]string{"carol": "u-carol"}, "me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []helper.GroupMembershipOperation{
+		{UserID: "u-ada", IsGroupManager: true},
+		{UserID: "u-dave", Delete: true},
+		{UserID: "u-carol"},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("ops = %+v, want %+v (own membership must be kept)", ops, want)
+	}
+
+	if _, err := membershipOperations(manifestGroup{Members: []string{"nobody"}}, nil, nil, "me"); err == nil {
+		t.Error("expected error for unknown user")
+	}
+}
+
+func TestRunResolverIgnoresUsernameCase(t *testing.T) {
+	p := &planner{state: &serverState{userIDs: map[string]string{userKey("Ada@Passbolt.com"): "u-ada"}}}
+	aro, id, err := p.runResolver()(manifestPermission{User: "ada@passbolt.com", Type: "read"})
+	if err != nil || aro != "User" || id != "u-ada" {
+		t.Errorf("runResolver = %v, %v, %v", aro, id, err)
+	}
+}
+
+func TestResourceUpdates(t *testing.T) {
+	current := resource.DecryptedResource{
+		Metadata: map[string]any{"name": "db", "username": "admin", "uri": "https://old"},
+		Secret:   map[string]any{"password": "hunter2", "description": "v4 description"},
+	}
+	password := "hunter2"
+	metadata, secret := resourceUpdates(manifestResource{
+		Name:        "db",
+		Username:    "admin",
+		URI:         "https://new",
+		Description: "v4 description",
+	}, &password, current)
+	if !reflect.DeepEqual(metadata, map[string]any{"uri": "https://new"}) || len(secret) != 0 {
+		t.Errorf("updates = %v, %v, want only uri", metadata, secret)
+	}
+
+	changed := "changed"
+	_, secret = resourceUpdates(manifestResource{Name: "db"}, &changed, current)
+	if secret["password"] != "changed" {
+		t.Errorf("password change not detected: %v", secret)
+	}
+}
+
+func TestExpiryChanged(t *testing.T) {
+	at := &api.Time{Time: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cases := []struct {
+		expiry  string
+		current *api.Time
+		want    bool
+	}{
+		{"", at, false},
+		{"none", nil, false},
+		{"none", at, true},
+		{"2030-01-01T00:00:00Z", at, false},
+		{"2030-01-01T01:00:00+01:00", at, false},
+		{"2031-01-01T00:00:00Z", at, true},
+		{"2030-01-01T00:00:00Z", nil, true},
+	}
+	for _, c := range cases {
+		if got := expiryChanged(c.expiry, c.current); got != c.want {
+			t.Errorf("expiryChanged(%q, %v) = %v, want %v", c.expiry, c.current, got, c.want)
+		}
+	}
+}
+
+func TestSortByDepth(t *testing.T) {
+	paths := []string{"a/b/c", "b", "a/b", "a"}
+	sortByDepth(paths)
+	if !reflect.DeepEqual(paths, []string{"a", "b", "a/b", "a/b/c"}) {
+		t.Errorf("sorted = %v", paths)
+	}
+}
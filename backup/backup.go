@@ -0,0 +1,163 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// BackupCmd Creates an encrypted Backup of Passbolt
+var BackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Creates an encrypted Backup of Passbolt",
+	Long: `Creates an encrypted Backup of all Resources (metadata and secrets), Folders, Tags and Permissions the User has access to.
+The Backup is a versioned JSON document encrypted to an OpenPGP key, by default the key of the current User.
+Use "passbolt restore" to recreate it on the same or a different Server.`,
+	Aliases: []string{},
+	RunE:    Backup,
+}
+
+func init() {
+	BackupCmd.Flags().StringP("file", "f", "passbolt-backup.json.asc", "File name of the Backup")
+	BackupCmd.Flags().String("recipient-key", "", "File containing the armored OpenPGP public key to encrypt the Backup to, defaults to the key of the current User")
+}
+
+func Backup(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	recipientKeyFile, err := cmd.Flags().GetString("recipient-key")
+	if err != nil {
+		return err
+	}
+	recipientKey := ""
+	if recipientKeyFile != "" {
+		data, err := os.ReadFile(recipientKeyFile)
+		if err != nil {
+			return fmt.Errorf("reading Recipient Key: %w", err)
+		}
+		recipientKey = string(data)
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	fmt.Println("Getting Folders...")
+	folders, err := client.GetFolders(ctx, &api.GetFoldersOptions{
+		ContainPermissions:           true,
+		ContainPermissionUserProfile: true,
+		ContainPermissionGroup:       true,
+	})
+	if err != nil {
+		return fmt.Errorf("getting Folders: %w", err)
+	}
+
+	fmt.Println("Getting Resources...")
+	resources, err := client.GetResources(ctx, &api.GetResourcesOptions{
+		ContainSecret:                 true,
+		ContainResourceType:           true,
+		ContainPermissions:            true,
+		ContainPermissionsUserProfile: true,
+		ContainPermissionsGroup:       true,
+		ContainTags:                   true,
+	})
+	if err != nil {
+		return fmt.Errorf("getting Resources: %w", err)
+	}
+
+	doc := document{
+		Created:   time.Now().UTC(),
+		Folders:   make([]folderRecord, 0, len(folders)),
+		Resources: make([]resourceRecord, 0, len(resources)),
+	}
+	for _, folder := range folders {
+		doc.Folders = append(doc.Folders, folderRecord{
+			ID:             folder.ID,
+			Name:           folder.Name,
+			FolderParentID: folder.FolderParentID,
+			Permissions:    permissionRecords(folder.Permissions),
+		})
+	}
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Decryping Resources").WithTotal(len(resources)).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	skipped := 0
+	for _, resource := range resources {
+		if len(resource.Secrets) == 0 {
+			fmt.Printf("\nSkipping Backup of Resource %v %v Because it has no Secret\n", resource.ID, resource.Name)
+			skipped++
+			progressbar.Increment()
+			continue
+		}
+		_, metadata, secretFields, err := helper.GetResourceFieldMaps(client, resource, resource.Secrets[0], resource.ResourceType, true)
+		if err != nil {
+			fmt.Printf("\nSkipping Backup of Resource %v %v Because of: %v\n", resource.ID, resource.Name, err)
+			skipped++
+			progressbar.Increment()
+			continue
+		}
+
+		record := resourceRecord{
+			ID:             resource.ID,
+			FolderParentID: resource.FolderParentID,
+			ResourceType:   resource.ResourceType.Slug,
+			Metadata:       metadata,
+			Secret:         secretFields,
+			Tags:           tagSlugs(resource.Tags),
+			Permissions:    permissionRecords(resource.Permissions),
+		}
+		if resource.Expired != nil {
+			expired := resource.Expired.UTC()
+			record.Expired = &expired
+		}
+		doc.Resources = append(doc.Resources, record)
+		progressbar.Increment()
+	}
+
+	data, err := marshalDocument(doc)
+	if err != nil {
+		return fmt.Errorf("marshalling Backup: %w", err)
+	}
+
+	var encrypted string
+	if recipientKey != "" {
+		encrypted, err = client.EncryptMessageWithPublicKey(recipientKey, string(data))
+	} else {
+		encrypted, err = client.EncryptMessage(string(data))
+	}
+	if err != nil {
+		return fmt.Errorf("encrypting Backup: %w", err)
+	}
+
+	err = os.WriteFile(filename, []byte(encrypted), 0600)
+	if err != nil {
+		return fmt.Errorf("writing Backup: %w", err)
+	}
+
+	fmt.Printf("Backed up %v Folders and %v Resources, Skipped %v Resources\n", len(doc.Folders), len(doc.Resources), skipped)
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package backup implements encrypted backup and restore of a whole Passbolt workspace.
+package backup
@@ -0,0 +1,119 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/passbolt/go-passbolt/api"
+)
+
+// documentVersion is bumped whenever the backup format changes incompatibly
+const documentVersion = 1
+
+// document is the plaintext content of a backup file
+type document struct {
+	Version   int              `json:"version"`
+	Created   time.Time        `json:"created"`
+	Folders   []folderRecord   `json:"folders"`
+	Resources []resourceRecord `json:"resources"`
+}
+
+type folderRecord struct {
+	ID             string             `json:"id"`
+	Name           string             `json:"name"`
+	FolderParentID string             `json:"folder_parent_id,omitempty"`
+	Permissions    []permissionRecord `json:"permissions,omitempty"`
+}
+
+type resourceRecord struct {
+	ID             string             `json:"id"`
+	FolderParentID string             `json:"folder_parent_id,omitempty"`
+	ResourceType   string             `json:"resource_type"`
+	Metadata       map[string]any     `json:"metadata"`
+	Secret         map[string]any     `json:"secret"`
+	Expired        *time.Time         `json:"expired,omitempty"`
+	Tags           []string           `json:"tags,omitempty"`
+	Permissions    []permissionRecord `json:"permissions,omitempty"`
+}
+
+// permissionRecord references Users by username and Groups by name, so they
+// can be found again on a different server.
+type permissionRecord struct {
+	ARO  string `json:"aro"`
+	Name string `json:"name"`
+	Type int    `json:"type"`
+}
+
+// serverMetadataKeys are metadata fields that are tied to the server the
+// backup was taken from and are set again on creation.
+var serverMetadataKeys = []string{"object_type", "resource_type_id"}
+
+func marshalDocument(doc document) ([]byte, error) {
+	doc.Version = documentVersion
+	return json.Marshal(doc)
+}
+
+func unmarshalDocument(data []byte) (document, error) {
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return document{}, fmt.Errorf("parsing backup: %w", err)
+	}
+	if doc.Version != documentVersion {
+		return document{}, fmt.Errorf("unsupported backup version %v, expected %v", doc.Version, documentVersion)
+	}
+	return doc, nil
+}
+
+// permissionRecords converts server Permissions, which must contain the User
+// profile or Group, into records. Permissions without either are dropped.
+func permissionRecords(permissions []api.Permission) []permissionRecord {
+	records := []permissionRecord{}
+	for _, p := range permissions {
+		switch {
+		case p.ARO == "User" && p.User != nil:
+			records = append(records, permissionRecord{ARO: p.ARO, Name: p.User.Username, Type: p.Type})
+		case p.ARO == "Group" && p.Group != nil:
+			records = append(records, permissionRecord{ARO: p.ARO, Name: p.Group.Name, Type: p.Type})
+		}
+	}
+	return records
+}
+
+// tagSlugs returns the slugs of tags, shared tags keep their leading #
+func tagSlugs(tags []api.Tag) []string {
+	slugs := make([]string, 0, len(tags))
+	for _, t := range tags {
+		slugs = append(slugs, t.Slug)
+	}
+	return slugs
+}
+
+// sortFoldersByDepth orders folders so that every Folder comes after its
+// parent. Folders whose parent is not part of the backup count as top level.
+func sortFoldersByDepth(folders []folderRecord) []folderRecord {
+	byID := make(map[string]folderRecord, len(folders))
+	for _, f := range folders {
+		byID[f.ID] = f
+	}
+	depth := func(f folderRecord) int {
+		d := 0
+		seen := map[string]bool{f.ID: true}
+		for {
+			parent, ok := byID[f.FolderParentID]
+			if !ok || seen[parent.ID] {
+				return d
+			}
+			seen[parent.ID] = true
+			f = parent
+			d++
+		}
+	}
+
+	sorted := append([]folderRecord{}, folders...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return depth(sorted[i]) < depth(sorted[j])
+	})
+	return sorted
+}
@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/passbolt/go-passbolt/api"
+)
+
+func TestDocument_RoundTrip(t *testing.T) {
+	doc := document{
+		Folders:   []folderRecord{{ID: "f1", Name: "Prod"}},
+		Resources: []resourceRecord{{ID: "r1", FolderParentID: "f1", ResourceType: "v5-default", Metadata: map[string]any{"name": "db"}}},
+	}
+	data, err := marshalDocument(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := unmarshalDocument(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != documentVersion || len(got.Folders) != 1 || got.Resources[0].Metadata["name"] != "db" {
+		t.Errorf("round trip = %+v", got)
+	}
+}
+
+func TestUnmarshalDocument_UnsupportedVersion(t *testing.T) {
+	_, err := unmarshalDocument([]byte(`{"version": 99}`))
+	if err == nil || !strings.Contains(err.Error(), "unsupported backup version") {
+		t.Errorf("err = %v, want unsupported version", err)
+	}
+}
+
+func TestSortFoldersByDepth(t *testing.T) {
+	folders := []folderRecord{
+		{ID: "c", FolderParentID: "b"},
+		{ID: "b", FolderParentID: "a"},
+		{ID: "a"},
+		{ID: "x", FolderParentID: "not-in-backup"},
+	}
+	sorted := sortFoldersByDepth(folders)
+	pos := map[string]int{}
+	for i, f := range sorted {
+		pos[f.ID] = i
+	}
+	if pos["a"] > pos["b"] || pos["b"] > pos["c"] {
+		t.Errorf("parents must come before children: %+v", sorted)
+	}
+	if pos["x"] > pos["b"] {
+		t.Errorf("folder with unknown parent should be top level: %+v", sorted)
+	}
+}
+
+func TestPermissionRecords(t *testing.T) {
+	records := permissionRecords([]api.Permission{
+		{ARO: "User", Type: 15, User: &api.User{Username: "ada@passbolt.test"}},
+		{ARO: "Group", Type: 1, Group: &api.Group{Name: "Ops"}},
+		{ARO: "User", Type: 7},
+	})
+	if len(records) != 2 || records[0].Name != "ada@passbolt.test" || records[1].Name != "Ops" {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestShareOperations(t *testing.T) {
+	aros := aroDirectory{
+		self:   "me",
+		users:  map[string]string{"me@passbolt.test": "me", "ada@passbolt.test": "u1"},
+		groups: map[string]string{"Ops": "g1"},
+	}
+	ops := aros.shareOperations([]permissionRecord{
+		{ARO: "User", Name: "me@passbolt.test", Type: 15},
+		{ARO: "User", Name: "ada@passbolt.test", Type: 7},
+		{ARO: "Group", Name: "Ops", Type: 1},
+		{ARO: "User", Name: "gone@passbolt.test", Type: 1},
+	}, "Resource db")
+	if len(ops) != 2 {
+		t.Fatalf("ops = %+v, want ada and Ops only", ops)
+	}
+	if ops[0].AROID != "u1" || ops[0].Type != 7 || ops[1].AROID != "g1" || ops[1].ARO != "Group" {
+		t.Errorf("ops = %+v", ops)
+	}
+}
@@ -0,0 +1,277 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// RestoreCmd Restores an encrypted Backup into Passbolt
+var RestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restores an encrypted Backup into Passbolt",
+	Long: `Restores a Backup created with "passbolt backup" by recreating its Folders and Resources with new IDs.
+The Backup must be encrypted to the key of the current User. Permissions are matched to Users by username and
+to Groups by name, Permissions for Users or Groups that do not exist on the Server are skipped with a warning.
+Resources are recreated with the Resource Type they had, so the Server must support it.`,
+	Aliases: []string{},
+	RunE:    Restore,
+}
+
+func init() {
+	RestoreCmd.Flags().StringP("file", "f", "", "File name of the Backup")
+	RestoreCmd.Flags().String("folderParentID", "", "Folder in which to restore, defaults to the root")
+	RestoreCmd.Flags().Bool("skip-permissions", false, "Do not restore Permissions, everything is only accessible to the current User")
+
+	RestoreCmd.MarkFlagRequired("file")
+}
+
+func Restore(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	folderParentID, err := cmd.Flags().GetString("folderParentID")
+	if err != nil {
+		return err
+	}
+	skipPermissions, err := cmd.Flags().GetBool("skip-permissions")
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("reading Backup: %w", err)
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	data, err := client.DecryptMessage(string(encrypted))
+	if err != nil {
+		return fmt.Errorf("decrypting Backup: %w", err)
+	}
+	doc, err := unmarshalDocument([]byte(data))
+	if err != nil {
+		return err
+	}
+
+	var aros aroDirectory
+	if !skipPermissions {
+		aros, err = getAroDirectory(ctx, client)
+		if err != nil {
+			return err
+		}
+	}
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Restoring").WithTotal(len(doc.Folders) + len(doc.Resources)).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	// Maps the Folder IDs of the Backup to the newly created ones
+	folderIDs := map[string]string{}
+	for _, folder := range sortFoldersByDepth(doc.Folders) {
+		parentID, ok := folderIDs[folder.FolderParentID]
+		if !ok {
+			parentID = folderParentID
+		}
+		id, err := util.CreateImportFolder(ctx, client, parentID, folder.Name)
+		if err != nil {
+			return fmt.Errorf("creating Folder %v: %w", folder.Name, err)
+		}
+		folderIDs[folder.ID] = id
+
+		if !skipPermissions {
+			ops := aros.shareOperations(folder.Permissions, "Folder "+folder.Name)
+			if len(ops) != 0 && util.DryRun() {
+				util.PrintDryRun("PUT", fmt.Sprintf("/share/folder/%s.json", id), "share Folder %q with %v", folder.Name, describeShareOperations(ops))
+			} else if len(ops) != 0 {
+				err = helper.ShareFolder(ctx, client, id, ops)
+				if err != nil {
+					fmt.Printf("\nSkipping Permissions of Folder %v Because of: %v\n", folder.Name, err)
+				}
+			}
+		}
+		progressbar.Increment()
+	}
+
+	restored, skipped := 0, 0
+	for _, record := range doc.Resources {
+		name := helper.GetStringField(record.Metadata, "name")
+		parentID, ok := folderIDs[record.FolderParentID]
+		if !ok {
+			parentID = folderParentID
+		}
+
+		metadata := map[string]any{}
+		for k, v := range record.Metadata {
+			metadata[k] = v
+		}
+		for _, k := range serverMetadataKeys {
+			delete(metadata, k)
+		}
+
+		if util.DryRun() {
+			permissions := "no Permissions"
+			if !skipPermissions {
+				permissions = describeShareOperations(aros.shareOperations(record.Permissions, "Resource "+name))
+			}
+			util.PrintDryRun("POST", "/resources.json", "create Resource %q of type %v in Folder %v with Tags %v and %v",
+				name, record.ResourceType, parentID, record.Tags, permissions)
+			if record.Expired != nil {
+				err = resource.SetResourceExpiry(ctx, client, "<new>", record.Expired.Format(time.RFC3339))
+				if err != nil {
+					return err
+				}
+			}
+			restored++
+			progressbar.Increment()
+			continue
+		}
+
+		id, err := helper.CreateResourceGeneric(ctx, client, record.ResourceType, parentID, metadata, record.Secret)
+		if err != nil {
+			fmt.Printf("\nSkipping Restore of Resource %v Because of: %v\n", name, err)
+			skipped++
+			progressbar.Increment()
+			continue
+		}
+		restored++
+
+		if record.Expired != nil {
+			err = resource.SetResourceExpiry(ctx, client, id, record.Expired.Format(time.RFC3339))
+			if err != nil {
+				fmt.Printf("\nSkipping Expiry of Resource %v Because of: %v\n", name, err)
+			}
+		}
+		if len(record.Tags) != 0 {
+			err = setResourceTags(ctx, client, id, record.Tags)
+			if err != nil {
+				fmt.Printf("\nSkipping Tags of Resource %v Because of: %v\n", name, err)
+			}
+		}
+		if !skipPermissions {
+			ops := aros.shareOperations(record.Permissions, "Resource "+name)
+			if len(ops) != 0 {
+				err = helper.ShareResource(ctx, client, id, ops)
+				if err != nil {
+					fmt.Printf("\nSkipping Permissions of Resource %v Because of: %v\n", name, err)
+				}
+			}
+		}
+		progressbar.Increment()
+	}
+
+	fmt.Printf("Restored %v Folders and %v Resources, Skipped %v Resources\n", len(folderIDs), restored, skipped)
+	return nil
+}
+
+// aroDirectory resolves the Users and Groups of a permissionRecord to their
+// IDs on the current Server.
+type aroDirectory struct {
+	self   string
+	users  map[string]string
+	groups map[string]string
+}
+
+func getAroDirectory(ctx context.Context, client *api.Client) (aroDirectory, error) {
+	users, err := client.GetUsers(ctx, nil)
+	if err != nil {
+		return aroDirectory{}, fmt.Errorf("getting Users: %w", err)
+	}
+	groups, err := client.GetGroups(ctx, nil)
+	if err != nil {
+		return aroDirectory{}, fmt.Errorf("getting Groups: %w", err)
+	}
+
+	aros := aroDirectory{
+		self:   util.CurrentUserID(client),
+		users:  make(map[string]string, len(users)),
+		groups: make(map[string]string, len(groups)),
+	}
+	for _, u := range users {
+		aros.users[u.Username] = u.ID
+	}
+	for _, g := range groups {
+		aros.groups[g.Name] = g.ID
+	}
+	return aros, nil
+}
+
+// shareOperations converts records into share operations. The current User
+// is skipped as they already own everything they restore.
+func (a aroDirectory) shareOperations(records []permissionRecord, what string) []helper.ShareOperation {
+	ops := []helper.ShareOperation{}
+	for _, p := range records {
+		var id string
+		var ok bool
+		switch p.ARO {
+		case "User":
+			id, ok = a.users[p.Name]
+		case "Group":
+			id, ok = a.groups[p.Name]
+		}
+		if !ok {
+			fmt.Printf("\nSkipping Permission of %v %v on %v, it does not exist on this Server\n", p.ARO, p.Name, what)
+			continue
+		}
+		if p.ARO == "User" && id == a.self {
+			continue
+		}
+		ops = append(ops, helper.ShareOperation{
+			Type:  p.Type,
+			ARO:   p.ARO,
+			AROID: id,
+		})
+	}
+	return ops
+}
+
+// setResourceTags replaces the Tags of a Resource
+func setResourceTags(ctx context.Context, client *api.Client, id string, tags []string) error {
+	// TODO: Should be handled in go-passbolt once it supports Tags
+	_, _, err := client.DoCustomRequestAndReturnRawResponseV5(
+		ctx,
+		"PUT",
+		fmt.Sprintf("tags/%s.json", id),
+		map[string]any{"tags": tags},
+		nil,
+	)
+	return err
+}
+
+// describeShareOperations describes share operations for dry-run output
+func describeShareOperations(ops []helper.ShareOperation) string {
+	if len(ops) == 0 {
+		return "no Permissions"
+	}
+	permissions := make([]string, 0, len(ops))
+	for _, op := range ops {
+		permissions = append(permissions, fmt.Sprintf("%v %v as %v", op.ARO, op.AROID, util.PermissionTypeName(op.Type)))
+	}
+	return fmt.Sprintf("Permissions %v", permissions)
+}
@@ -0,0 +1,2 @@
+// Package bitwarden implements import of unencrypted Bitwarden JSON exports.
+package bitwarden
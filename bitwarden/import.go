@@ -0,0 +1,329 @@
+package bitwarden
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// BitwardenImportCmd Imports a Bitwarden JSON Export into Passbolt
+var BitwardenImportCmd = &cobra.Command{
+	Use:   "bitwarden",
+	Short: "Imports a Bitwarden JSON Export into Passbolt",
+	Long: `Imports an unencrypted Bitwarden JSON Export into Passbolt.
+Logins, Secure Notes, Cards, Identities and SSH Keys are imported, Folders (and Collections of Organization Exports) are recreated as Folders.
+Card, Identity and SSH Key details and custom fields are imported as custom fields, which require v5 Resource Types.`,
+	Aliases: []string{},
+	RunE:    BitwardenImport,
+}
+
+func init() {
+	BitwardenImportCmd.Flags().StringP("file", "f", "", "File name of the Bitwarden JSON Export")
+	BitwardenImportCmd.Flags().String("folderParentID", "", "Folder in which to import, defaults to the root")
+
+	BitwardenImportCmd.MarkFlagRequired("file")
+}
+
+// Bitwarden item types
+const (
+	bitwardenTypeLogin      = 1
+	bitwardenTypeSecureNote = 2
+	bitwardenTypeCard       = 3
+	bitwardenTypeIdentity   = 4
+	bitwardenTypeSSHKey     = 5
+)
+
+// bitwardenFieldLinked is the custom field type that references another
+// field of the item instead of holding a value
+const bitwardenFieldLinked = 3
+
+type bitwardenExport struct {
+	Encrypted   bool                  `json:"encrypted"`
+	Folders     []bitwardenFolder     `json:"folders"`
+	Collections []bitwardenCollection `json:"collections"`
+	Items       []bitwardenItem       `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenCollection struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	ID            string             `json:"id"`
+	FolderID      string             `json:"folderId"`
+	CollectionIDs []string           `json:"collectionIds"`
+	Type          int                `json:"type"`
+	Name          string             `json:"name"`
+	Notes         string             `json:"notes"`
+	Fields        []bitwardenField   `json:"fields"`
+	Login         *bitwardenLogin    `json:"login"`
+	Card          *bitwardenCard     `json:"card"`
+	Identity      *bitwardenIdentity `json:"identity"`
+	SSHKey        *bitwardenSSHKey   `json:"sshKey"`
+}
+
+type bitwardenField struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value"`
+	Type  int     `json:"type"`
+}
+
+type bitwardenLogin struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	TOTP     string `json:"totp"`
+	URIs     []struct {
+		URI string `json:"uri"`
+	} `json:"uris"`
+}
+
+type bitwardenCard struct {
+	CardholderName string `json:"cardholderName"`
+	Brand          string `json:"brand"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"expMonth"`
+	ExpYear        string `json:"expYear"`
+	Code           string `json:"code"`
+}
+
+type bitwardenIdentity struct {
+	Title          string `json:"title"`
+	FirstName      string `json:"firstName"`
+	MiddleName     string `json:"middleName"`
+	LastName       string `json:"lastName"`
+	Address1       string `json:"address1"`
+	Address2       string `json:"address2"`
+	Address3       string `json:"address3"`
+	City           string `json:"city"`
+	State          string `json:"state"`
+	PostalCode     string `json:"postalCode"`
+	Country        string `json:"country"`
+	Company        string `json:"company"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	SSN            string `json:"ssn"`
+	Username       string `json:"username"`
+	PassportNumber string `json:"passportNumber"`
+	LicenseNumber  string `json:"licenseNumber"`
+}
+
+type bitwardenSSHKey struct {
+	PrivateKey     string `json:"privateKey"`
+	PublicKey      string `json:"publicKey"`
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+func BitwardenImport(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	folderParentID, err := cmd.Flags().GetString("folderParentID")
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("reading File: %w", err)
+	}
+	export, err := parseBitwardenExport(data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	folderNames := map[string]string{}
+	for _, f := range export.Folders {
+		folderNames[f.ID] = f.Name
+	}
+	for _, c := range export.Collections {
+		folderNames[c.ID] = c.Name
+	}
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Importing Resources").WithTotal(len(export.Items)).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	folders := util.NewFolderPathCreator(client, folderParentID)
+	imported, skipped := 0, 0
+	for _, item := range export.Items {
+		entry, ok := bitwardenEntry(item)
+		if !ok {
+			fmt.Printf("\nSkipping Import of Item %v Because its Type %v is not supported\n", item.Name, item.Type)
+			skipped++
+			progressbar.Increment()
+			continue
+		}
+
+		folderID, err := folders.Ensure(ctx, bitwardenFolderPath(item, folderNames))
+		if err != nil {
+			return err
+		}
+
+		_, err = util.CreateImportEntry(ctx, client, folderID, entry)
+		if err != nil {
+			fmt.Printf("\nSkipping Import of Item %v Because of: %v\n", item.Name, err)
+			skipped++
+		} else {
+			imported++
+		}
+		progressbar.Increment()
+	}
+
+	fmt.Printf("Imported %v Folders and %v Resources, Skipped %v Items\n", folders.Created(), imported, skipped)
+	return nil
+}
+
+func parseBitwardenExport(data []byte) (bitwardenExport, error) {
+	var export bitwardenExport
+	err := json.Unmarshal(data, &export)
+	if err != nil {
+		return bitwardenExport{}, fmt.Errorf("parsing Bitwarden Export: %w", err)
+	}
+	if export.Encrypted {
+		return bitwardenExport{}, fmt.Errorf("encrypted Bitwarden Exports are not supported, export as unencrypted JSON instead")
+	}
+	return export, nil
+}
+
+// bitwardenFolderPath returns the Folder path of an item. Bitwarden nests
+// Folders and Collections by separating names with a slash.
+func bitwardenFolderPath(item bitwardenItem, folderNames map[string]string) []string {
+	name := folderNames[item.FolderID]
+	if name == "" && len(item.CollectionIDs) > 0 {
+		name = folderNames[item.CollectionIDs[0]]
+	}
+	if name == "" {
+		return nil
+	}
+	return strings.Split(name, "/")
+}
+
+// bitwardenEntry maps a Bitwarden item onto an ImportEntry, reporting false
+// for item types it does not know.
+func bitwardenEntry(item bitwardenItem) (util.ImportEntry, bool) {
+	entry := util.ImportEntry{
+		Name:        item.Name,
+		Description: item.Notes,
+	}
+
+	switch item.Type {
+	case bitwardenTypeLogin:
+		if item.Login != nil {
+			entry.Username = item.Login.Username
+			entry.Password = item.Login.Password
+			for _, u := range item.Login.URIs {
+				if u.URI != "" {
+					entry.URIs = append(entry.URIs, u.URI)
+				}
+			}
+			if item.Login.TOTP != "" {
+				totp, err := util.ParseTOTP(item.Login.TOTP)
+				if err != nil {
+					fmt.Printf("Ignoring TOTP of Item %v Because of: %v\n", item.Name, err)
+				} else {
+					entry.TOTP = totp
+				}
+			}
+		}
+	case bitwardenTypeSecureNote:
+	case bitwardenTypeCard:
+		if item.Card != nil {
+			expiration := ""
+			if item.Card.ExpMonth != "" || item.Card.ExpYear != "" {
+				expiration = item.Card.ExpMonth + "/" + item.Card.ExpYear
+			}
+			entry.CustomFields = appendFields(entry.CustomFields,
+				"Cardholder Name", item.Card.CardholderName,
+				"Brand", item.Card.Brand,
+				"Number", item.Card.Number,
+				"Expiration", expiration,
+				"Security Code", item.Card.Code,
+			)
+		}
+	case bitwardenTypeIdentity:
+		if item.Identity != nil {
+			id := item.Identity
+			entry.Username = id.Username
+			if entry.Username == "" {
+				entry.Username = id.Email
+			}
+			entry.CustomFields = appendFields(entry.CustomFields,
+				"Title", id.Title,
+				"First Name", id.FirstName,
+				"Middle Name", id.MiddleName,
+				"Last Name", id.LastName,
+				"Address 1", id.Address1,
+				"Address 2", id.Address2,
+				"Address 3", id.Address3,
+				"City", id.City,
+				"State", id.State,
+				"Postal Code", id.PostalCode,
+				"Country", id.Country,
+				"Company", id.Company,
+				"Email", id.Email,
+				"Phone", id.Phone,
+				"SSN", id.SSN,
+				"Passport Number", id.PassportNumber,
+				"License Number", id.LicenseNumber,
+			)
+		}
+	case bitwardenTypeSSHKey:
+		if item.SSHKey != nil {
+			entry.CustomFields = appendFields(entry.CustomFields,
+				"Private Key", item.SSHKey.PrivateKey,
+				"Public Key", item.SSHKey.PublicKey,
+				"Fingerprint", item.SSHKey.KeyFingerprint,
+			)
+		}
+	default:
+		return util.ImportEntry{}, false
+	}
+
+	for _, field := range item.Fields {
+		if field.Type == bitwardenFieldLinked || field.Value == nil {
+			continue
+		}
+		entry.CustomFields = append(entry.CustomFields, util.ImportCustomField{Key: field.Name, Value: *field.Value})
+	}
+	return entry, true
+}
+
+// appendFields appends key/value pairs as custom fields, skipping empty values
+func appendFields(fields []util.ImportCustomField, pairs ...string) []util.ImportCustomField {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if pairs[i+1] != "" {
+			fields = append(fields, util.ImportCustomField{Key: pairs[i], Value: pairs[i+1]})
+		}
+	}
+	return fields
+}
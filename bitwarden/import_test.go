@@ -0,0 +1,99 @@
+package bitwarden
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+)
+
+const testExport = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Prod/Databases"}],
+  "items": [
+    {
+      "id": "i1", "folderId": "f1", "type": 1, "name": "postgres", "notes": "primary",
+      "fields": [
+        {"name": "port", "value": "5432", "type": 0},
+        {"name": "linked", "value": null, "type": 3, "linkedId": 100}
+      ],
+      "login": {
+        "username": "admin", "password": "hunter2", "totp": "JBSWY3DPEHPK3PXP",
+        "uris": [{"match": null, "uri": "https://db1"}, {"match": null, "uri": "https://db2"}]
+      }
+    },
+    {
+      "id": "i2", "folderId": null, "type": 3, "name": "Visa",
+      "card": {"cardholderName": "Ada", "brand": "Visa", "number": "4111", "expMonth": "1", "expYear": "2030", "code": "123"}
+    },
+    {"id": "i3", "type": 99, "name": "future"}
+  ]
+}`
+
+func TestBitwardenEntry_Login(t *testing.T) {
+	export, err := parseBitwardenExport([]byte(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := bitwardenEntry(export.Items[0])
+	if !ok {
+		t.Fatal("login item not supported")
+	}
+	if entry.Username != "admin" || entry.Password != "hunter2" || entry.Description != "primary" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if !reflect.DeepEqual(entry.URIs, []string{"https://db1", "https://db2"}) {
+		t.Errorf("uris = %v", entry.URIs)
+	}
+	if entry.TOTP["secret_key"] != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("totp = %v", entry.TOTP)
+	}
+	if !reflect.DeepEqual(entry.CustomFields, []util.ImportCustomField{{Key: "port", Value: "5432"}}) {
+		t.Errorf("custom fields = %+v, linked fields should be skipped", entry.CustomFields)
+	}
+
+	folderNames := map[string]string{"f1": "Prod/Databases"}
+	if path := bitwardenFolderPath(export.Items[0], folderNames); !reflect.DeepEqual(path, []string{"Prod", "Databases"}) {
+		t.Errorf("folder path = %v", path)
+	}
+	if path := bitwardenFolderPath(export.Items[1], folderNames); path != nil {
+		t.Errorf("item without folder should go to the root, got %v", path)
+	}
+}
+
+func TestBitwardenEntry_Card(t *testing.T) {
+	export, err := parseBitwardenExport([]byte(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := bitwardenEntry(export.Items[1])
+	if !ok {
+		t.Fatal("card item not supported")
+	}
+	want := []util.ImportCustomField{
+		{Key: "Cardholder Name", Value: "Ada"},
+		{Key: "Brand", Value: "Visa"},
+		{Key: "Number", Value: "4111"},
+		{Key: "Expiration", Value: "1/2030"},
+		{Key: "Security Code", Value: "123"},
+	}
+	if !reflect.DeepEqual(entry.CustomFields, want) {
+		t.Errorf("custom fields = %+v", entry.CustomFields)
+	}
+}
+
+func TestBitwardenEntry_UnknownType(t *testing.T) {
+	export, err := parseBitwardenExport([]byte(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bitwardenEntry(export.Items[2]); ok {
+		t.Error("unknown item type should not be supported")
+	}
+}
+
+func TestParseBitwardenExport_Encrypted(t *testing.T) {
+	if _, err := parseBitwardenExport([]byte(`{"encrypted": true, "items": []}`)); err == nil {
+		t.Error("expected error for encrypted export")
+	}
+}
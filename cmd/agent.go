@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/passbolt/go-passbolt-cli/agent"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// localCommands are never run by the agent, they either need no client, need
+// the local process or get the Resources they need from the agent themselves
+var localCommands = map[string]bool{
+	"agent":      true,
+	"completion": true,
+	"configure":  true,
+	"exec":       true,
+	"help":       true,
+	"logout":     true,
+	"render":     true,
+	"verify":     true,
+}
+
+// localFlags make a command run locally, as it then runs until interrupted
+var localFlags = []string{"watch"}
+
+func init() {
+	rootCmd.AddCommand(agent.AgentCmd)
+	rootCmd.PersistentPreRunE = forwardToAgent
+	agent.Execute = executeInAgent
+}
+
+// runsInAgent returns if cmd may be run by the agent, its flags must be parsed
+func runsInAgent(cmd *cobra.Command) bool {
+	for _, name := range localFlags {
+		if cmd.Flags().Changed(name) {
+			return false
+		}
+	}
+	for cmd.HasParent() && cmd.Parent() != rootCmd {
+		cmd = cmd.Parent()
+	}
+	return cmd != rootCmd && !localCommands[cmd.Name()]
+}
+
+// forwardToAgent runs the invocation in an unlocked agent instead, if there is one
+func forwardToAgent(cmd *cobra.Command, args []string) error {
+	if util.InAgent() || !runsInAgent(cmd) {
+		return nil
+	}
+	ok, code := util.RunInAgent(os.Args[1:])
+	if !ok {
+		return nil
+	}
+	// The agent already reported any error
+	cmd.SilenceErrors = true
+	return &exitCodeError{code: code}
+}
+
+// executeInAgent runs an invocation forwarded to the agent until ctx ends
+func executeInAgent(ctx context.Context, args []string) int {
+	resetFlags(rootCmd)
+	cmd, flags, err := rootCmd.Find(args)
+	// Invalid flags are reported by Execute below
+	if err != nil || (cmd.ParseFlags(flags) == nil && !runsInAgent(cmd)) {
+		fmt.Fprintln(os.Stderr, "Error: the Agent does not run this command")
+		return 1
+	}
+
+	resetFlags(rootCmd)
+	viper.Reset()
+	bindFlags()
+	rootCmd.SetArgs(args)
+	err = rootCmd.ExecuteContext(ctx)
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	} else if err != nil {
+		return 1
+	}
+	return 0
+}
+
+// resetFlags sets all flags back to their defaults, so an invocation run by
+// the agent does not see the flags of the one before
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if v, ok := f.Value.(pflag.SliceValue); ok {
+			v.Replace(sliceDefault(f.DefValue))
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+// sliceDefault parses the default of a slice flag, which is shown like [a,b]
+func sliceDefault(def string) []string {
+	def = strings.TrimSuffix(strings.TrimPrefix(def, "["), "]")
+	if def == "" {
+		return []string{}
+	}
+	return strings.Split(def, ",")
+}
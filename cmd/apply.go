@@ -0,0 +1,9 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/apply"
+)
+
+func init() {
+	rootCmd.AddCommand(apply.ApplyCmd)
+}
@@ -0,0 +1,10 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/backup"
+)
+
+func init() {
+	rootCmd.AddCommand(backup.BackupCmd)
+	rootCmd.AddCommand(backup.RestoreCmd)
+}
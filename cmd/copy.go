@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/folder"
+	"github.com/spf13/cobra"
+)
+
+// copyCmd represents the copy command
+var copyCmd = &cobra.Command{
+	Use:     "copy",
+	Short:   "Copies a Passbolt Entity",
+	Long:    `Copies a Passbolt Entity`,
+	Aliases: []string{"cp"},
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+	copyCmd.AddCommand(folder.FolderCopyCmd)
+}
@@ -23,5 +23,5 @@ func init() {
 	deleteCmd.AddCommand(group.GroupDeleteCmd)
 	deleteCmd.AddCommand(user.UserDeleteCmd)
 
-	deleteCmd.PersistentFlags().String("id", "", "ID of the Entity to Delete")
+	deleteCmd.PersistentFlags().String("id", "", "ID, name or path of the Entity to Delete")
 }
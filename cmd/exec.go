@@ -2,20 +2,21 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 
+	"github.com/passbolt/go-passbolt-cli/lookup"
 	"github.com/passbolt/go-passbolt-cli/util"
-	"github.com/passbolt/go-passbolt/api"
-	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-const PassboltPrefix = "passbolt://"
-
 // execCmd represents the exec command
 var execCmd = &cobra.Command{
 	Use:   "exec -- command [args...]",
@@ -30,30 +31,114 @@ Any environment variables containing passbolt:// references are automatically re
 	passbolt exec -- gh auth login
 
 	This would resolve the passbolt:// reference in GITHUB_TOKEN to its actual secret value and pass it to the gh process.
+
+	References select the password by default, other values can be selected by appending a selector:
+	passbolt://<PASSBOLT_RESOURCE_ID_HERE>/username
+	passbolt://<PASSBOLT_RESOURCE_ID_HERE>/totp
+	passbolt://<PASSBOLT_RESOURCE_ID_HERE>/secret/custom_fields/<key>
+
+	Resources can also be referenced by Folder path and name, with the selector after a #:
+	passbolt://folder/Prod/DB#username
+
+	Variables can also be loaded from dotenv Files with --env-file, and Secrets that Tools only read from Files
+	can be written to a private temporary Directory with --secret-file, which is removed when the command exits:
+	passbolt exec --env-file .env --secret-file TLS_KEY=passbolt://folder/Prod/tls#password -- sh -c 'server --key "$TLS_KEY"'
 `,
 	Args: cobra.MinimumNArgs(1),
 	RunE: execAction,
 }
 
 func init() {
+	execCmd.Flags().StringArray("env-file", []string{}, "dotenv File to load Variables from, Values may be passbolt:// References")
+	execCmd.Flags().StringArray("secret-file", []string{}, "NAME=passbolt://... writes the Secret to a File with 0600 Permissions and sets NAME to its Path")
+	execCmd.Flags().Bool("mask", false, "Replace the resolved Secrets with *** in the Output of the command")
+	execCmd.Flags().Bool("replace", false, "Replace the CLI Process with the command instead of running it as a Child, not supported with --secret-file and on Windows")
+
 	rootCmd.AddCommand(execCmd)
 }
 
-func execAction(_ *cobra.Command, args []string) error {
+func execAction(cmd *cobra.Command, args []string) error {
+	envFiles, err := cmd.Flags().GetStringArray("env-file")
+	if err != nil {
+		return err
+	}
+	secretFiles, err := cmd.Flags().GetStringArray("secret-file")
+	if err != nil {
+		return err
+	}
+	replace, err := cmd.Flags().GetBool("replace")
+	if err != nil {
+		return err
+	}
+	mask, err := cmd.Flags().GetBool("mask")
+	if err != nil {
+		return err
+	}
+	if replace && mask {
+		return fmt.Errorf("--replace can't be used with --mask, as nobody would be left to mask the output")
+	}
+	if replace && len(secretFiles) > 0 {
+		return fmt.Errorf("--replace can't be used with --secret-file, as nobody would be left to remove the files")
+	}
+
+	env := os.Environ()
+	for _, path := range envFiles {
+		vars, err := readEnvFile(path)
+		if err != nil {
+			return err
+		}
+		env = util.MergeEnv(env, vars)
+	}
+	for _, secretFile := range secretFiles {
+		name, value, ok := strings.Cut(secretFile, "=")
+		if !ok || name == "" || !lookup.IsReference(value) {
+			return fmt.Errorf("invalid secret-file %q, must be NAME=passbolt://...", secretFile)
+		}
+		// The name becomes the file name in the secret file directory
+		if name != filepath.Base(name) || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid secret-file name %q, must be a plain file name", name)
+		}
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 	defer cancel()
 
-	client, err := util.GetClient(ctx)
+	l, done, err := lookup.Open(ctx)
 	if err != nil {
 		return fmt.Errorf("creating client: %w", err)
 	}
 
-	envVars, err := resolveEnvironmentSecrets(ctx, client)
+	prefetchReferences(ctx, l, append(append([]string{}, env...), secretFiles...))
+	envVars, secrets, err := resolveEnvironmentSecrets(ctx, l, env)
 	if err != nil {
 		return fmt.Errorf("resolving secrets: %w", err)
 	}
+	fileSecrets, fileSecretValues, err := resolveEnvironmentSecrets(ctx, l, secretFiles)
+	if err != nil {
+		return fmt.Errorf("resolving secret files: %w", err)
+	}
 
-	util.SaveSessionKeysAndLogout(ctx, client)
+	done()
+	cmd.SilenceUsage = true
+
+	if replace {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			return fmt.Errorf("running command: %w", err)
+		}
+		return fmt.Errorf("running command: %w", replaceProcess(path, args, envVars))
+	}
+
+	if len(fileSecrets) > 0 {
+		dir, fileVars, err := writeSecretFiles(fileSecrets)
+		if dir != "" {
+			defer os.RemoveAll(dir)
+		}
+		if err != nil {
+			return err
+		}
+		envVars = util.MergeEnv(envVars, fileVars)
+	}
 
 	subCmd := exec.Command(args[0], args[1:]...)
 	subCmd.Stdin = os.Stdin
@@ -61,15 +146,90 @@ func execAction(_ *cobra.Command, args []string) error {
 	subCmd.Stderr = os.Stderr
 	subCmd.Env = envVars
 
-	if err = subCmd.Run(); err != nil {
+	if mask {
+		secrets = append(secrets, fileSecretValues...)
+		stdout := util.NewMaskingWriter(os.Stdout, secrets)
+		stderr := util.NewMaskingWriter(os.Stderr, secrets)
+		defer stdout.Flush()
+		defer stderr.Flush()
+		subCmd.Stdout = stdout
+		subCmd.Stderr = stderr
+	}
+
+	// Forward signals instead of dying from them, so the command can shut
+	// down cleanly and the secret files get removed afterwards
+	signals := make(chan os.Signal, 8)
+	signal.Notify(signals, forwardedSignals...)
+	defer signal.Stop(signals)
+
+	if err = subCmd.Start(); err != nil {
+		return fmt.Errorf("running command: %w", err)
+	}
+	go func() {
+		for sig := range signals {
+			subCmd.Process.Signal(sig)
+		}
+	}()
+
+	err = subCmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// The command already reported its error, only pass on the exit code
+		cmd.SilenceErrors = true
+		return &exitCodeError{code: exitCode(exitErr)}
+	} else if err != nil {
 		return fmt.Errorf("running command: %w", err)
 	}
 
 	return nil
 }
 
-func resolveEnvironmentSecrets(ctx context.Context, client *api.Client) ([]string, error) {
-	envVars := os.Environ()
+// exitCode returns the exit code of a command, using the shell convention of
+// 128 + signal number for commands killed by a signal
+func exitCode(err *exec.ExitError) int {
+	if status, ok := err.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return 128 + int(status.Signal())
+	}
+	return err.ExitCode()
+}
+
+func readEnvFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	defer file.Close()
+
+	vars, err := util.ParseEnvFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("parsing env file %v: %w", path, err)
+	}
+	return vars, nil
+}
+
+// prefetchReferences fetches all Resources referenced by the KEY=VALUE pairs
+// in parallel, errors are reported when resolving them
+func prefetchReferences(ctx context.Context, l *lookup.Lookup, env []string) {
+	refs := []string{}
+	for _, envVar := range env {
+		_, value, _ := strings.Cut(envVar, "=")
+		if !lookup.IsReference(value) {
+			continue
+		}
+		ref, err := lookup.ParseReference(value)
+		if err == nil {
+			refs = append(refs, ref.Resource)
+		}
+	}
+	l.Prefetch(ctx, refs)
+}
+
+// resolveEnvironmentSecrets replaces the values of KEY=VALUE pairs that are
+// passbolt:// references and also returns the resolved secrets
+func resolveEnvironmentSecrets(ctx context.Context, l *lookup.Lookup, env []string) ([]string, []string, error) {
+	envVars := make([]string, len(env))
+	copy(envVars, env)
+	secrets := []string{}
 
 	for i, envVar := range envVars {
 		splitIndex := strings.Index(envVar, "=")
@@ -80,22 +240,46 @@ func resolveEnvironmentSecrets(ctx context.Context, client *api.Client) ([]strin
 		key := envVar[:splitIndex]
 		value := envVar[splitIndex+1:]
 
-		if !strings.HasPrefix(value, PassboltPrefix) {
+		if !lookup.IsReference(value) {
 			continue
 		}
 
-		resourceID := strings.TrimPrefix(value, PassboltPrefix)
-		_, _, _, _, secret, _, err := helper.GetResource(ctx, client, resourceID)
+		ref, err := lookup.ParseReference(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", key, err)
+		}
+		secret, err := l.Value(ctx, ref)
 		if err != nil {
-			return nil, fmt.Errorf("getting resource: %w", err)
+			return nil, nil, fmt.Errorf("%v: %w", key, err)
 		}
 
 		envVars[i] = key + "=" + secret
+		secrets = append(secrets, secret)
 
 		if viper.GetBool("debug") {
-			fmt.Printf("%v env var populated with resource id %v\n", key, resourceID)
+			fmt.Printf("%v env var populated with %v\n", key, value)
 		}
 	}
 
-	return envVars, nil
+	return envVars, secrets, nil
+}
+
+// writeSecretFiles writes each NAME=secret pair to a file in a new private
+// directory and returns NAME=path pairs for the environment
+func writeSecretFiles(secrets []string) (string, []string, error) {
+	dir, err := os.MkdirTemp("", "passbolt-exec-")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating secret file directory: %w", err)
+	}
+	vars := []string{}
+	for _, s := range secrets {
+		name, secret, _ := strings.Cut(s, "=")
+		path := filepath.Join(dir, name)
+		err = os.WriteFile(path, []byte(secret), 0600)
+		if err != nil {
+			return dir, nil, fmt.Errorf("writing secret file %v: %w", name, err)
+		}
+		vars = append(vars, name+"="+path)
+	}
+	return dir, vars, nil
 }
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// forwardedSignals are passed on to the command run by exec
+var forwardedSignals = []os.Signal{os.Interrupt}
+
+func replaceProcess(path string, args, env []string) error {
+	return fmt.Errorf("replacing the process is not supported on %v", runtime.GOOS)
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// forwardedSignals are passed on to the command run by exec
+var forwardedSignals = []os.Signal{
+	syscall.SIGINT,
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+	syscall.SIGQUIT,
+	syscall.SIGUSR1,
+	syscall.SIGUSR2,
+	syscall.SIGWINCH,
+}
+
+// replaceProcess replaces the CLI with the command, keeping the PID
+func replaceProcess(path string, args, env []string) error {
+	return syscall.Exec(path, args, env)
+}
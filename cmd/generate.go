@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/generate"
+	"github.com/spf13/cobra"
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generates Secrets",
+	Long:  `Generates Secrets like Passwords locally`,
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	generateCmd.AddCommand(generate.GeneratePasswordCmd)
+}
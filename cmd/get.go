@@ -23,5 +23,6 @@ func init() {
 	getCmd.AddCommand(folder.FolderGetCmd)
 	getCmd.AddCommand(group.GroupGetCmd)
 	getCmd.AddCommand(user.UserGetCmd)
+	getCmd.AddCommand(resource.ResourceTOTPCmd)
 
 }
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/bitwarden"
+	"github.com/passbolt/go-passbolt-cli/csvexchange"
+	"github.com/passbolt/go-passbolt-cli/keepass"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Imports Data into Passbolt",
+	Long:  `Imports Data into Passbolt`,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(keepass.KeepassImportCmd)
+	importCmd.AddCommand(csvexchange.CSVImportCmd)
+	importCmd.AddCommand(bitwarden.BitwardenImportCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+)
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Logout clears the Session Cache",
+	Long: `Logout clears the Session Cache of the configured Server and User.
+After this the next Invocation has to log in and complete the MFA Challenge again.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		removed, err := util.ClearSessionCache()
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Println("Session Cache Cleared")
+		} else {
+			fmt.Println("No Session Cache")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logoutCmd)
+}
@@ -0,0 +1,9 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/render"
+)
+
+func init() {
+	rootCmd.AddCommand(render.RenderCmd)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/report"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Reports on Passbolt Entitys",
+	Long:  `Reports on Passbolt Entitys`,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.PersistentFlags().BoolP("json", "j", false, "Output JSON")
+	reportCmd.AddCommand(report.ReportExpiryCmd)
+}
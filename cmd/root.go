@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,11 +28,23 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
-	if err != nil {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	} else if err != nil {
 		os.Exit(1)
 	}
 }
 
+// exitCodeError makes the CLI exit with the exit code of a command it ran
+type exitCodeError struct {
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return fmt.Sprintf("command exited with code %v", e.code)
+}
+
 func init() {
 	pterm.DisableStyling()
 
@@ -60,6 +73,11 @@ func init() {
 	rootCmd.PersistentFlags().Uint("mfaRetrys", 3, "How often to retry TOTP Auth, only used in nointeractive modes")
 	rootCmd.PersistentFlags().Duration("mfaDelay", time.Second*10, "Delay between MFA Attempts, only used in noninteractive modes")
 
+	rootCmd.PersistentFlags().Bool("sessionCache", false, "Cache the Session and MFA Verification encrypted in the Config Directory, so later Invocations reuse the Session instead of logging in again")
+	rootCmd.PersistentFlags().Duration("sessionCacheTTL", time.Hour*12, "How long the cached Session and MFA Verification are used, only used if sessionCache is enabled")
+
+	rootCmd.PersistentFlags().String("agentSocket", "", "Unix Socket of the Passbolt Agent, defaults to go-passbolt-cli/agent.sock in the Runtime or Config Directory")
+
 	rootCmd.PersistentFlags().Bool("tlsSkipVerify", false, "Allow servers with self-signed certificates")
 	rootCmd.PersistentFlags().String("tlsClientPrivateKeyFile", "", "Client private key path for mtls")
 	rootCmd.PersistentFlags().String("tlsClientCertFile", "", "Client certificate path for mtls")
@@ -67,7 +85,13 @@ func init() {
 	rootCmd.PersistentFlags().String("tlsClientCert", "", "Client certificate for mtls")
 
 	rootCmd.PersistentFlags().Uint("workers", 0, "Number of Concurrent Workers for Expensive Operations. 0 (default) uses the number of CPU cores")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Print the API Calls that would be made and the Entities that would change, without changing anything")
 
+	bindFlags()
+}
+
+// bindFlags binds the global flags to their config keys
+func bindFlags() {
 	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
 	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
 	viper.BindPFlag("serverAddress", rootCmd.PersistentFlags().Lookup("serverAddress"))
@@ -80,12 +104,17 @@ func init() {
 	viper.BindPFlag("mfaTotpOffset", rootCmd.PersistentFlags().Lookup("mfaTotpOffset"))
 	viper.BindPFlag("mfaRetrys", rootCmd.PersistentFlags().Lookup("mfaRetrys"))
 	viper.BindPFlag("mfaDelay", rootCmd.PersistentFlags().Lookup("mfaDelay"))
+	viper.BindPFlag("sessionCache", rootCmd.PersistentFlags().Lookup("sessionCache"))
+	viper.BindPFlag("sessionCacheTTL", rootCmd.PersistentFlags().Lookup("sessionCacheTTL"))
+
+	viper.BindPFlag("agentSocket", rootCmd.PersistentFlags().Lookup("agentSocket"))
 
 	viper.BindPFlag("tlsSkipVerify", rootCmd.PersistentFlags().Lookup("tlsSkipVerify"))
 	viper.BindPFlag("tlsClientCert", rootCmd.PersistentFlags().Lookup("tlsClientCert"))
 	viper.BindPFlag("tlsClientPrivateKey", rootCmd.PersistentFlags().Lookup("tlsClientPrivateKey"))
 
 	viper.BindPFlag("workers", rootCmd.PersistentFlags().Lookup("workers"))
+	viper.BindPFlag("dryRun", rootCmd.PersistentFlags().Lookup("dry-run"))
 }
 
 func fileToContent(file, contentFlag string) {
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/spf13/cobra"
+)
+
+// rotateCmd represents the rotate command
+var rotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotates the Secret of a Passbolt Entity",
+	Long:  `Rotates the Secret of a Passbolt Entity`,
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+	rotateCmd.AddCommand(resource.ResourceRotateCmd)
+}
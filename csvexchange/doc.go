@@ -0,0 +1,3 @@
+// Package csvexchange implements CSV export and import in the formats of
+// other password managers.
+package csvexchange
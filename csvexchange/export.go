@@ -0,0 +1,166 @@
+package csvexchange
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// CSVExportCmd Exports Passbolt to a CSV File
+var CSVExportCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Exports Passbolt to a CSV File",
+	Long: `Exports Passbolt to a CSV File in the format of another password manager.
+The file contains unencrypted secrets and is created with 0600 permissions.`,
+	Aliases: []string{},
+	RunE:    CSVExport,
+}
+
+func init() {
+	CSVExportCmd.Flags().StringP("file", "f", "passbolt-export.csv", "File name of the CSV File")
+	CSVExportCmd.Flags().String("profile", "passbolt", "CSV layout to write: "+profileNames())
+	CSVExportCmd.Flags().StringArray("map", []string{}, "Override the column header of a field as field=Header (repeatable), fields: name, username, uri, password, description, folder, totp, custom_fields")
+}
+
+func CSVExport(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	profileName, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return err
+	}
+	mappings, err := cmd.Flags().GetStringArray("map")
+	if err != nil {
+		return err
+	}
+	profile, err := getProfile(profileName, mappings)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	fmt.Println("Getting Resources...")
+	resources, err := client.GetResources(ctx, &api.GetResourcesOptions{
+		ContainSecret:       true,
+		ContainResourceType: true,
+	})
+	if err != nil {
+		return fmt.Errorf("getting Resources: %w", err)
+	}
+
+	fmt.Println("Getting Folders...")
+	folders, err := client.GetFolders(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("getting Folders: %w", err)
+	}
+	folderPaths := util.FolderPaths(folders)
+
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("creating File: %w", err)
+	}
+	defer file.Close()
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Decryping Resources").WithTotal(len(resources)).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	header := make([]string, len(profile.columns))
+	for i, column := range profile.columns {
+		header[i] = column.header
+	}
+	records := [][]string{header}
+
+	for _, resource := range resources {
+		if len(resource.Secrets) == 0 {
+			progressbar.Increment()
+			continue
+		}
+		_, metadata, secretFields, err := helper.GetResourceFieldMaps(client, resource, resource.Secrets[0], resource.ResourceType, true)
+		if err != nil {
+			fmt.Printf("\nSkipping Export of Resource %v %v Because of: %v\n", resource.ID, resource.Name, err)
+			progressbar.Increment()
+			continue
+		}
+
+		records = append(records, csvRecord(profile, metadata, secretFields, folderPaths[resource.FolderParentID]))
+		progressbar.Increment()
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(records); err != nil {
+		return fmt.Errorf("writing csv: %w", err)
+	}
+	fmt.Println("Done")
+
+	return nil
+}
+
+// csvRecord renders one Resource as a row in the column order of profile
+func csvRecord(profile csvProfile, metadata, secretFields map[string]any, folderPath []string) []string {
+	name := helper.GetStringField(metadata, "name")
+	username := helper.GetStringField(metadata, "username")
+	uri := helper.GetStringField(metadata, "uri")
+
+	record := make([]string, len(profile.columns))
+	for i, column := range profile.columns {
+		switch column.field {
+		case fieldName:
+			record[i] = name
+		case fieldUsername:
+			record[i] = username
+		case fieldURI:
+			record[i] = uri
+		case fieldPassword:
+			record[i] = helper.GetStringField(secretFields, "password")
+		case fieldDescription:
+			record[i] = helper.GetStringField(metadata, "description")
+		case fieldFolder:
+			if len(folderPath) > 0 || profile.rootGroup != "" {
+				record[i] = profile.joinFolderPath(folderPath)
+			}
+		case fieldTOTP:
+			if totp, ok := secretFields["totp"].(map[string]any); ok {
+				issuer := uri
+				if uri == "" {
+					issuer = name
+				}
+				accountName := username
+				if username == "" {
+					accountName = name
+				}
+				record[i] = util.TOTPURI(totp, issuer, accountName)
+			}
+		case fieldCustomFields:
+			record[i] = formatCustomFields(util.CustomFields(metadata, secretFields))
+		default:
+			record[i] = column.value
+		}
+	}
+	return record
+}
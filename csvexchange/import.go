@@ -0,0 +1,194 @@
+package csvexchange
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// CSVImportCmd Imports a CSV File into Passbolt
+var CSVImportCmd = &cobra.Command{
+	Use:     "csv",
+	Short:   "Imports a CSV File into Passbolt",
+	Long:    `Imports a CSV File in the format of another password manager into Passbolt. Folder paths are recreated as Folders`,
+	Aliases: []string{},
+	RunE:    CSVImport,
+}
+
+func init() {
+	CSVImportCmd.Flags().StringP("file", "f", "", "File name of the CSV File")
+	CSVImportCmd.Flags().String("profile", "passbolt", "CSV layout to read: "+profileNames())
+	CSVImportCmd.Flags().StringArray("map", []string{}, "Read a field from the given column header as field=Header (repeatable), fields: name, username, uri, password, description, folder, totp, custom_fields")
+	CSVImportCmd.Flags().String("folderParentID", "", "Folder in which to import, defaults to the root")
+
+	CSVImportCmd.MarkFlagRequired("file")
+}
+
+// csvImportRow is one parsed CSV row
+type csvImportRow struct {
+	entry      util.ImportEntry
+	folderPath []string
+}
+
+func CSVImport(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	profileName, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return err
+	}
+	mappings, err := cmd.Flags().GetStringArray("map")
+	if err != nil {
+		return err
+	}
+	profile, err := getProfile(profileName, mappings)
+	if err != nil {
+		return err
+	}
+	folderParentID, err := cmd.Flags().GetString("folderParentID")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("opening File: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("reading csv: %w", err)
+	}
+
+	rows, err := parseCSVRecords(profile, records)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Importing Resources").WithTotal(len(rows)).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	folders := util.NewFolderPathCreator(client, folderParentID)
+	imported, skipped := 0, 0
+	for _, row := range rows {
+		folderID, err := folders.Ensure(ctx, row.folderPath)
+		if err != nil {
+			return err
+		}
+
+		_, err = util.CreateImportEntry(ctx, client, folderID, row.entry)
+		if err != nil {
+			fmt.Printf("\nSkipping Import of Entry %v Because of: %v\n", row.entry.Name, err)
+			skipped++
+		} else {
+			imported++
+		}
+		progressbar.Increment()
+	}
+
+	fmt.Printf("Imported %v Folders and %v Resources, Skipped %v Entries\n", folders.Created(), imported, skipped)
+	return nil
+}
+
+// parseCSVRecords maps the rows of a CSV file onto entries using the header
+// row to locate the columns of profile.
+func parseCSVRecords(profile csvProfile, records [][]string) ([]csvImportRow, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("the CSV File is empty")
+	}
+
+	header := records[0]
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	columns := map[string]int{}
+	for _, column := range profile.columns {
+		if column.field == "" {
+			continue
+		}
+		if i, ok := index[strings.ToLower(column.header)]; ok {
+			columns[column.field] = i
+		}
+	}
+	if _, ok := columns[fieldName]; !ok {
+		if _, ok := columns[fieldPassword]; !ok {
+			return nil, fmt.Errorf("the CSV header %v has neither a name nor a password column for this profile", header)
+		}
+	}
+
+	rows := []csvImportRow{}
+	for n, record := range records[1:] {
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return record[i]
+		}
+
+		row := csvImportRow{
+			entry: util.ImportEntry{
+				Name:        get(fieldName),
+				Username:    get(fieldUsername),
+				Password:    get(fieldPassword),
+				Description: get(fieldDescription),
+			},
+			folderPath: profile.splitFolderPath(get(fieldFolder)),
+		}
+		if uri := get(fieldURI); uri != "" {
+			row.entry.URIs = []string{uri}
+		}
+		if otp := get(fieldTOTP); otp != "" {
+			totp, err := util.ParseTOTP(otp)
+			if err != nil {
+				fmt.Printf("Ignoring TOTP of Row %v Because of: %v\n", n+2, err)
+			} else {
+				row.entry.TOTP = totp
+			}
+		}
+		if fields := get(fieldCustomFields); fields != "" {
+			row.entry.CustomFields = parseCustomFields(fields)
+		}
+
+		if row.entry.Name == "" && row.entry.Password == "" && row.entry.TOTP == nil {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,208 @@
+package csvexchange
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+)
+
+// Fields a CSV column can be mapped to
+const (
+	fieldName         = "name"
+	fieldUsername     = "username"
+	fieldURI          = "uri"
+	fieldPassword     = "password"
+	fieldDescription  = "description"
+	fieldFolder       = "folder"
+	fieldTOTP         = "totp"
+	fieldCustomFields = "custom_fields"
+)
+
+var csvFields = []string{fieldName, fieldUsername, fieldURI, fieldPassword, fieldDescription, fieldFolder, fieldTOTP, fieldCustomFields}
+
+// csvColumn maps a CSV header to a Resource field. Columns without a field
+// are written with a constant value on export and ignored on import.
+type csvColumn struct {
+	header string
+	field  string
+	value  string
+}
+
+// csvProfile describes the CSV layout of a password manager
+type csvProfile struct {
+	columns []csvColumn
+	// folderSeparator joins the Folder path in the folder column
+	folderSeparator string
+	// rootGroup is prepended to every Folder path on export and stripped on import
+	rootGroup string
+}
+
+var csvProfiles = map[string]csvProfile{
+	// the layout of the CSV export of the Passbolt web UI
+	"passbolt": {
+		columns: []csvColumn{
+			{header: "Group", field: fieldFolder},
+			{header: "Title", field: fieldName},
+			{header: "Username", field: fieldUsername},
+			{header: "Password", field: fieldPassword},
+			{header: "URL", field: fieldURI},
+			{header: "Notes", field: fieldDescription},
+			{header: "TOTP", field: fieldTOTP},
+		},
+		folderSeparator: "/",
+	},
+	"keepassxc": {
+		columns: []csvColumn{
+			{header: "Group", field: fieldFolder},
+			{header: "Title", field: fieldName},
+			{header: "Username", field: fieldUsername},
+			{header: "Password", field: fieldPassword},
+			{header: "URL", field: fieldURI},
+			{header: "Notes", field: fieldDescription},
+			{header: "TOTP", field: fieldTOTP},
+		},
+		folderSeparator: "/",
+		rootGroup:       "Root",
+	},
+	"bitwarden": {
+		columns: []csvColumn{
+			{header: "folder", field: fieldFolder},
+			{header: "favorite"},
+			{header: "type", value: "login"},
+			{header: "name", field: fieldName},
+			{header: "notes", field: fieldDescription},
+			{header: "fields", field: fieldCustomFields},
+			{header: "reprompt", value: "0"},
+			{header: "login_uri", field: fieldURI},
+			{header: "login_username", field: fieldUsername},
+			{header: "login_password", field: fieldPassword},
+			{header: "login_totp", field: fieldTOTP},
+		},
+		folderSeparator: "/",
+	},
+	"1password": {
+		columns: []csvColumn{
+			{header: "Title", field: fieldName},
+			{header: "Url", field: fieldURI},
+			{header: "Username", field: fieldUsername},
+			{header: "Password", field: fieldPassword},
+			{header: "OTPAuth", field: fieldTOTP},
+			{header: "Favorite", value: "false"},
+			{header: "Archived", value: "false"},
+			{header: "Tags"},
+			{header: "Notes", field: fieldDescription},
+		},
+	},
+	"lastpass": {
+		columns: []csvColumn{
+			{header: "url", field: fieldURI},
+			{header: "username", field: fieldUsername},
+			{header: "password", field: fieldPassword},
+			{header: "totp", field: fieldTOTP},
+			{header: "extra", field: fieldDescription},
+			{header: "name", field: fieldName},
+			{header: "grouping", field: fieldFolder},
+			{header: "fav", value: "0"},
+		},
+		folderSeparator: "\\",
+	},
+}
+
+func profileNames() string {
+	names := make([]string, 0, len(csvProfiles))
+	for name := range csvProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// getProfile returns the named profile with the field=Header overrides from
+// mappings applied. An override replaces the header of a mapped field, or
+// adds a new column if the profile has no column for that field.
+func getProfile(name string, mappings []string) (csvProfile, error) {
+	base, ok := csvProfiles[strings.ToLower(name)]
+	if !ok {
+		return csvProfile{}, fmt.Errorf("unknown profile %q: must be one of %v", name, profileNames())
+	}
+	profile := base
+	profile.columns = append([]csvColumn{}, base.columns...)
+
+	for _, mapping := range mappings {
+		field, header, ok := strings.Cut(mapping, "=")
+		if !ok || header == "" {
+			return csvProfile{}, fmt.Errorf("invalid mapping %q: expected field=Header", mapping)
+		}
+		field = strings.ToLower(field)
+		if !isCSVField(field) {
+			return csvProfile{}, fmt.Errorf("invalid mapping %q: unknown field %q, must be one of %v", mapping, field, strings.Join(csvFields, ", "))
+		}
+
+		replaced := false
+		for i := range profile.columns {
+			if profile.columns[i].field == field {
+				profile.columns[i].header = header
+				replaced = true
+			}
+		}
+		if !replaced {
+			profile.columns = append(profile.columns, csvColumn{header: header, field: field})
+		}
+	}
+	return profile, nil
+}
+
+func isCSVField(field string) bool {
+	for _, f := range csvFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// joinFolderPath renders a Folder path for the folder column
+func (p csvProfile) joinFolderPath(path []string) string {
+	if p.rootGroup != "" {
+		path = append([]string{p.rootGroup}, path...)
+	}
+	return strings.Join(path, p.folderSeparator)
+}
+
+// splitFolderPath parses the folder column into a Folder path
+func (p csvProfile) splitFolderPath(s string) []string {
+	if s == "" || p.folderSeparator == "" {
+		return nil
+	}
+	path := strings.Split(s, p.folderSeparator)
+	if p.rootGroup != "" && len(path) > 0 && path[0] == p.rootGroup {
+		path = path[1:]
+	}
+	return path
+}
+
+// formatCustomFields renders custom fields as "key: value" lines, the way
+// Bitwarden writes its fields column.
+func formatCustomFields(fields []util.ImportCustomField) string {
+	lines := make([]string, len(fields))
+	for i, f := range fields {
+		lines[i] = f.Key + ": " + f.Value
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseCustomFields is the inverse of formatCustomFields
+func parseCustomFields(s string) []util.ImportCustomField {
+	fields := []util.ImportCustomField{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(line, ": ")
+		fields = append(fields, util.ImportCustomField{Key: key, Value: value})
+	}
+	return fields
+}
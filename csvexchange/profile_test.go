@@ -0,0 +1,124 @@
+package csvexchange
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetProfile_Overrides(t *testing.T) {
+	profile, err := getProfile("LastPass", []string{"uri=Website", "custom_fields=Extra Fields"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.columns[0].header != "Website" || profile.columns[0].field != fieldURI {
+		t.Errorf("uri column = %+v, want header Website", profile.columns[0])
+	}
+	last := profile.columns[len(profile.columns)-1]
+	if last.header != "Extra Fields" || last.field != fieldCustomFields {
+		t.Errorf("unmapped field should be appended, got %+v", last)
+	}
+	if csvProfiles["lastpass"].columns[0].header != "url" {
+		t.Errorf("override modified the base profile")
+	}
+}
+
+func TestGetProfile_Invalid(t *testing.T) {
+	if _, err := getProfile("nope", nil); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+	if _, err := getProfile("passbolt", []string{"colour=Color"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := getProfile("passbolt", []string{"name"}); err == nil {
+		t.Error("expected error for mapping without header")
+	}
+}
+
+func TestFolderPath_RoundTrip(t *testing.T) {
+	path := []string{"Prod", "Databases"}
+	for name, profile := range csvProfiles {
+		if profile.folderSeparator == "" {
+			continue
+		}
+		got := profile.splitFolderPath(profile.joinFolderPath(path))
+		if !reflect.DeepEqual(got, path) {
+			t.Errorf("%v: round trip = %v, want %v", name, got, path)
+		}
+	}
+	if got := csvProfiles["keepassxc"].joinFolderPath(path); got != "Root/Prod/Databases" {
+		t.Errorf("keepassxc path = %q", got)
+	}
+}
+
+func TestParseCSVRecords(t *testing.T) {
+	profile, err := getProfile("bitwarden", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := [][]string{
+		{"\ufefffolder", "favorite", "type", "name", "notes", "fields", "reprompt", "login_uri", "login_username", "login_password", "login_totp"},
+		{"Prod/DB", "", "login", "postgres", "note", "port: 5432\nhost: db", "0", "https://db", "admin", "hunter2", "JBSWY3DPEHPK3PXP"},
+		{"", "", "note", "", "", "", "0", "", "", "", ""},
+	}
+
+	rows, err := parseCSVRecords(profile, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1 (empty rows are skipped)", len(rows))
+	}
+	row := rows[0]
+	if row.entry.Name != "postgres" || row.entry.Username != "admin" || row.entry.Password != "hunter2" || row.entry.URIs[0] != "https://db" {
+		t.Errorf("entry = %+v", row.entry)
+	}
+	if !reflect.DeepEqual(row.folderPath, []string{"Prod", "DB"}) {
+		t.Errorf("folder path = %v", row.folderPath)
+	}
+	if row.entry.TOTP["secret_key"] != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("bare totp secret not parsed: %v", row.entry.TOTP)
+	}
+	if len(row.entry.CustomFields) != 2 || row.entry.CustomFields[1].Key != "host" || row.entry.CustomFields[1].Value != "db" {
+		t.Errorf("custom fields = %+v", row.entry.CustomFields)
+	}
+}
+
+func TestParseCSVRecords_PassboltExport(t *testing.T) {
+	profile, err := getProfile("passbolt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header and row as written by the CSV export of the Passbolt web UI
+	records := [][]string{
+		{"\ufeffGroup", "Title", "Username", "Password", "URL", "Notes", "TOTP"},
+		{"Prod/Databases", "postgres", "admin", "hunter2", "https://db", "primary", "otpauth://totp/db?secret=JBSWY3DPEHPK3PXP"},
+	}
+
+	rows, err := parseCSVRecords(profile, records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	row := rows[0]
+	if row.entry.Name != "postgres" || row.entry.Username != "admin" || row.entry.Password != "hunter2" || row.entry.Description != "primary" {
+		t.Errorf("entry = %+v", row.entry)
+	}
+	if len(row.entry.URIs) != 1 || row.entry.URIs[0] != "https://db" {
+		t.Errorf("uris = %v", row.entry.URIs)
+	}
+	if !reflect.DeepEqual(row.folderPath, []string{"Prod", "Databases"}) {
+		t.Errorf("folder path = %v", row.folderPath)
+	}
+	if row.entry.TOTP["secret_key"] != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("totp not parsed: %v", row.entry.TOTP)
+	}
+}
+
+func TestParseCSVRecords_NoMatchingColumns(t *testing.T) {
+	profile, _ := getProfile("passbolt", nil)
+	if _, err := parseCSVRecords(profile, [][]string{{"foo", "bar"}}); err == nil {
+		t.Error("expected error when no profile column matches the header")
+	}
+}
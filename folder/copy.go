@@ -0,0 +1,186 @@
+package folder
+
+import (
+	"fmt"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/spf13/cobra"
+)
+
+// FolderCopyCmd Copies a Passbolt Folder
+var FolderCopyCmd = &cobra.Command{
+	Use:   "folder",
+	Short: "Copies a Passbolt Folder",
+	Long: `Copies a Passbolt Folder and the Resources in it into another Folder, with --recursive including all Subfolders.
+The copies get new IDs and their Secrets are encrypted for the current User only. Permissions, Tags and Expiry are not copied.`,
+	RunE: FolderCopy,
+}
+
+func init() {
+	FolderCopyCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to Copy")
+	FolderCopyCmd.Flags().String("to", "", "Folder in which to create the Copy, by id or path, / for the root")
+	FolderCopyCmd.Flags().BoolP("recursive", "r", false, "Also copy all Subfolders and their Resources")
+
+	FolderCopyCmd.MarkFlagRequired("id")
+	FolderCopyCmd.MarkFlagRequired("to")
+}
+
+// copiedMetadataKeys are metadata fields that belong to the original
+// Resource and are set again on creation
+var copiedMetadataKeys = []string{"object_type", "resource_type_id"}
+
+func FolderCopy(cmd *cobra.Command, args []string) error {
+	id, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return err
+	}
+	to, err := cmd.Flags().GetString("to")
+	if err != nil {
+		return err
+	}
+	recursive, err := cmd.Flags().GetBool("recursive")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	resolver := util.NewResolver(client)
+	id, err = resolver.Folder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return fmt.Errorf("the root Folder can't be copied")
+	}
+	to, err = resolver.Folder(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	folders, err := client.GetFolders(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing Folder: %w", err)
+	}
+	subtree, err := folderSubtree(folders, id, recursive)
+	if err != nil {
+		return err
+	}
+
+	folderIDs := make([]string, len(subtree))
+	for i, f := range subtree {
+		if f.ID == to {
+			return fmt.Errorf("can't copy Folder %v into itself", subtree[0].Name)
+		}
+		folderIDs[i] = f.ID
+	}
+
+	resources, err := client.GetResources(ctx, &api.GetResourcesOptions{
+		FilterHasParent:     folderIDs,
+		ContainSecret:       true,
+		ContainResourceType: true,
+	})
+	if err != nil {
+		return fmt.Errorf("listing Resource: %w", err)
+	}
+
+	// Maps the IDs of the copied Folders to the newly created ones
+	newIDs := map[string]string{}
+	for _, f := range subtree {
+		parentID, ok := newIDs[f.FolderParentID]
+		if !ok {
+			parentID = to
+		}
+		newID, err := util.CreateImportFolder(ctx, client, parentID, f.Name)
+		if err != nil {
+			return fmt.Errorf("creating Folder %v: %w", f.Name, err)
+		}
+		newIDs[f.ID] = newID
+	}
+
+	copied, skipped := 0, 0
+	for _, r := range resources {
+		parentID, ok := newIDs[r.FolderParentID]
+		if !ok {
+			continue
+		}
+		if len(r.Secrets) == 0 {
+			fmt.Printf("Skipping Copy of Resource %v Because it has no Secret\n", r.ID)
+			skipped++
+			continue
+		}
+		_, metadata, secret, err := helper.GetResourceFieldMaps(client, r, r.Secrets[0], r.ResourceType, true)
+		if err != nil {
+			fmt.Printf("Skipping Copy of Resource %v Because of: %v\n", r.ID, err)
+			skipped++
+			continue
+		}
+		for _, k := range copiedMetadataKeys {
+			delete(metadata, k)
+		}
+		name := helper.GetStringField(metadata, "name")
+
+		if util.DryRun() {
+			util.PrintDryRun("POST", "/resources.json", "create Resource %q of type %v in Folder %v", name, r.ResourceType.Slug, parentID)
+			copied++
+			continue
+		}
+		_, err = helper.CreateResourceGeneric(ctx, client, r.ResourceType.Slug, parentID, metadata, secret)
+		if err != nil {
+			fmt.Printf("Skipping Copy of Resource %v Because of: %v\n", name, err)
+			skipped++
+			continue
+		}
+		copied++
+	}
+
+	if util.DryRun() {
+		return nil
+	}
+	fmt.Printf("Copied %v Folders and %v Resources, Skipped %v Resources\n", len(newIDs), copied, skipped)
+	fmt.Printf("FolderID: %v\n", newIDs[id])
+	return nil
+}
+
+// folderSubtree returns the Folder rootID followed by its Subfolders if
+// recursive is set, every Folder comes after its parent
+func folderSubtree(folders []api.Folder, rootID string, recursive bool) ([]api.Folder, error) {
+	children := map[string][]api.Folder{}
+	var root *api.Folder
+	for i, f := range folders {
+		if f.ID == rootID {
+			root = &folders[i]
+		}
+		children[f.FolderParentID] = append(children[f.FolderParentID], f)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("folder %v not found", rootID)
+	}
+
+	subtree := []api.Folder{*root}
+	if !recursive {
+		return subtree, nil
+	}
+	seen := map[string]bool{rootID: true}
+	for i := 0; i < len(subtree); i++ {
+		for _, child := range children[subtree[i].ID] {
+			// guards against a cyclic hierarchy from a malformed response
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			subtree = append(subtree, child)
+		}
+	}
+	return subtree, nil
+}
@@ -0,0 +1,44 @@
+package folder
+
+import (
+	"testing"
+
+	"github.com/passbolt/go-passbolt/api"
+)
+
+func TestFolderSubtree(t *testing.T) {
+	folders := []api.Folder{
+		{ID: "c", FolderParentID: "b", Name: "C"},
+		{ID: "b", FolderParentID: "a", Name: "B"},
+		{ID: "a", Name: "A"},
+		{ID: "d", FolderParentID: "a", Name: "D"},
+		{ID: "other", Name: "Other"},
+	}
+
+	subtree, err := folderSubtree(folders, "a", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := map[string]int{}
+	for i, f := range subtree {
+		pos[f.ID] = i
+	}
+	if len(subtree) != 4 || pos["a"] != 0 {
+		t.Fatalf("unexpected subtree %+v", subtree)
+	}
+	if _, ok := pos["other"]; ok {
+		t.Errorf("subtree contains an unrelated Folder")
+	}
+	if pos["c"] < pos["b"] {
+		t.Errorf("Folder c comes before its parent b")
+	}
+
+	subtree, err = folderSubtree(folders, "a", false)
+	if err != nil || len(subtree) != 1 || subtree[0].ID != "a" {
+		t.Errorf("non recursive subtree = %+v, %v", subtree, err)
+	}
+
+	if _, err := folderSubtree(folders, "missing", true); err == nil {
+		t.Errorf("expected an error for a missing Folder")
+	}
+}
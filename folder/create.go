@@ -19,7 +19,7 @@ var FolderCreateCmd = &cobra.Command{
 
 func init() {
 	FolderCreateCmd.Flags().StringP("name", "n", "", "Folder Name")
-	FolderCreateCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to create the Folder")
+	FolderCreateCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to create the Folder, by id or path")
 
 	FolderCreateCmd.MarkFlagRequired("name")
 }
@@ -48,6 +48,20 @@ func FolderCreate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	folderParentID, err = util.NewResolver(client).Folder(ctx, folderParentID)
+	if err != nil {
+		return err
+	}
+
+	if util.DryRun() {
+		folder, err := util.DescribeFolder(ctx, client, folderParentID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("POST", "/folders.json", "create Folder %q in %v", name, folder)
+		return nil
+	}
+
 	id, err := helper.CreateFolder(
 		ctx,
 		client,
@@ -1,9 +1,13 @@
 package folder
 
 import (
+	"context"
 	"fmt"
 
+	"al.essio.dev/pkg/shellescape"
+	"github.com/passbolt/go-passbolt-cli/resource"
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/spf13/cobra"
 )
 
@@ -11,8 +15,18 @@ import (
 var FolderDeleteCmd = &cobra.Command{
 	Use:   "folder",
 	Short: "Deletes a Passbolt Folder",
-	Long:  `Deletes a Passbolt Folder`,
-	RunE:  FolderDelete,
+	Long: `Deletes a Passbolt Folder, or all Folders matching --filter after a Confirmation.
+Without --recursive only the Folder itself is deleted and the Server keeps its content.
+With --recursive all Subfolders and Resources in it are deleted too, after typing the Folder name to confirm.
+Use --cascade-resources=false to move the Resources to the root instead of deleting them.`,
+	RunE: FolderDelete,
+}
+
+func init() {
+	FolderDeleteCmd.Flags().String("filter", "", "CEL expression selecting the Folders to delete instead of --id, see \"list folder --filter\"")
+	FolderDeleteCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter or --recursive")
+	FolderDeleteCmd.Flags().BoolP("recursive", "r", false, "Also delete all Subfolders and Resources in the Folder")
+	FolderDeleteCmd.Flags().Bool("cascade-resources", true, "Delete the Resources with --recursive, if false they are moved to the root")
 }
 
 func FolderDelete(cmd *cobra.Command, args []string) error {
@@ -20,10 +34,35 @@ func FolderDelete(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+	recursive, err := cmd.Flags().GetBool("recursive")
+	if err != nil {
+		return err
+	}
+	cascadeResources, err := cmd.Flags().GetBool("cascade-resources")
+	if err != nil {
+		return err
+	}
 
-	if folderID == "" {
+	if folderID == "" && filter == "" {
 		return fmt.Errorf("no ID to Delete Provided")
 	}
+	if folderID != "" && filter != "" {
+		return fmt.Errorf("--id can't be used with --filter")
+	}
+	if recursive && filter != "" {
+		return fmt.Errorf("--recursive can't be used with --filter")
+	}
+	if cmd.Flags().Changed("cascade-resources") && !recursive {
+		return fmt.Errorf("--cascade-resources requires --recursive")
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -35,9 +74,116 @@ func FolderDelete(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = client.DeleteFolder(ctx, folderID)
+	folderID, err = util.NewResolver(client).Folder(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectFolderTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Deleted", "Folders", yes, func(id string) error {
+			return deleteFolder(ctx, client, id)
+		})
+	}
+	if recursive {
+		return deleteFolderRecursive(ctx, client, folderID, cascadeResources, yes)
+	}
+	return deleteFolder(ctx, client, folderID)
+}
+
+func deleteFolder(ctx context.Context, client *api.Client, folderID string) error {
+	if util.DryRun() {
+		folder, err := util.DescribeFolder(ctx, client, folderID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("DELETE", fmt.Sprintf("/folders/%s.json", folderID), "delete %v", folder)
+		return nil
+	}
+
+	err := client.DeleteFolder(ctx, folderID)
 	if err != nil {
 		return fmt.Errorf("deleting Folder: %w", err)
 	}
 	return nil
 }
+
+// deleteFolderRecursive deletes a Folder with all its Subfolders and, if
+// cascadeResources is set, the Resources in them. Otherwise the Resources are
+// moved to the root first. It asks to type the Folder name unless yes is set.
+func deleteFolderRecursive(ctx context.Context, client *api.Client, folderID string, cascadeResources, yes bool) error {
+	folders, err := client.GetFolders(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing Folder: %w", err)
+	}
+	subtree, err := folderSubtree(folders, folderID, true)
+	if err != nil {
+		return err
+	}
+	folderIDs := make([]string, len(subtree))
+	inSubtree := map[string]bool{}
+	for i, f := range subtree {
+		folderIDs[i] = f.ID
+		inSubtree[f.ID] = true
+	}
+
+	resources, err := client.GetResources(ctx, &api.GetResourcesOptions{
+		FilterHasParent: folderIDs,
+	})
+	if err != nil {
+		return fmt.Errorf("listing Resource: %w", err)
+	}
+	resourceIDs := []string{}
+	for _, r := range resources {
+		if inSubtree[r.FolderParentID] {
+			resourceIDs = append(resourceIDs, r.ID)
+		}
+	}
+
+	name := subtree[0].Name
+	fmt.Printf("Folder %q contains %v Subfolders and %v Resources\n", shellescape.StripUnsafe(name), len(subtree)-1, len(resourceIDs))
+	if cascadeResources {
+		fmt.Println("The Resources will be deleted")
+	} else {
+		fmt.Println("The Resources will be moved to the root")
+	}
+
+	if !yes && !util.DryRun() {
+		ok, err := util.ConfirmTyped("This can't be undone.", name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted, the name did not match")
+		}
+	}
+
+	action, fn := "Deleted", func(id string) error {
+		return resource.DeleteResource(ctx, client, id)
+	}
+	if !cascadeResources {
+		action, fn = "Moved", func(id string) error {
+			return resource.MoveResource(ctx, client, id, "")
+		}
+	}
+	errs := util.ForEachParallel(resourceIDs, fn)
+	// Keep the Folders if a Resource is left so nothing is removed by surprise
+	if err := util.PrintBulkSummary(action, "Resources", len(resourceIDs), errs); err != nil {
+		return fmt.Errorf("the Folders were not deleted: %w", err)
+	}
+
+	// Delete the deepest Folders first
+	for i := len(subtree) - 1; i >= 0; i-- {
+		err = deleteFolder(ctx, client, subtree[i].ID)
+		if err != nil {
+			return err
+		}
+	}
+	if !util.DryRun() {
+		fmt.Printf("Deleted %v Folders\n", len(subtree))
+	}
+	return nil
+}
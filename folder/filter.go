@@ -54,3 +54,20 @@ func filterFolders(folders *[]api.Folder, celCmd string, ctx context.Context) ([
 
 	return filteredFolders, nil
 }
+
+// selectFolderTargets returns the Folders matching a CEL expression for a bulk operation
+func selectFolderTargets(ctx context.Context, client *api.Client, celCmd string) ([]util.BulkTarget, error) {
+	folders, err := client.GetFolders(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing Folder: %w", err)
+	}
+	folders, err = filterFolders(&folders, celCmd, ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]util.BulkTarget, len(folders))
+	for i, f := range folders {
+		targets[i] = util.BulkTarget{ID: f.ID, Description: f.Name}
+	}
+	return targets, nil
+}
@@ -32,12 +32,12 @@ var FolderPermissionCmd = &cobra.Command{
 }
 
 func init() {
-	FolderGetCmd.Flags().String("id", "", "id of Folder to Get")
+	FolderGetCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to Get")
 
 	FolderGetCmd.MarkFlagRequired("id")
 
 	FolderGetCmd.AddCommand(FolderPermissionCmd)
-	FolderPermissionCmd.Flags().String("id", "", "id of Folder to get permissions for")
+	FolderPermissionCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to get permissions for")
 	FolderPermissionCmd.Flags().StringArrayP("column", "c", []string{"ID", "Aco", "AcoForeignKey", "Aro", "AroForeignKey", "Type"}, "Columns to return, possible Columns:\nID, Aco, AcoForeignKey, Aro, AroForeignKey, Type, CreatedTimestamp, ModifiedTimestamp")
 
 	FolderPermissionCmd.MarkFlagRequired("id")
@@ -63,6 +63,11 @@ func FolderGet(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).Folder(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	folder, err := client.GetFolder(ctx, id, nil)
 	if err != nil {
 		return fmt.Errorf("getting Folder: %w", err)
@@ -110,6 +115,11 @@ func FolderPermission(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	folderID, err = util.NewResolver(client).Folder(ctx, folderID)
+	if err != nil {
+		return err
+	}
+
 	folder, err := client.GetFolder(ctx, folderID, &api.GetFolderOptions{
 		ContainPermissions: true,
 	})
@@ -31,6 +31,8 @@ func init() {
 	flags.StringArrayP("folder", "f", []string{}, "Folders that are in this Folder")
 	flags.StringArrayP("group", "g", []string{}, "Folders that are shared with group")
 	flags.StringArrayP("column", "c", defaultTableColumns, "Columns to return (default list only for table format; JSON format includes all fields by default).\nPossible Columns: ID, FolderParentID, Name, CreatedTimestamp, ModifiedTimestamp")
+	flags.Bool("tree", false, "Show the Folder hierarchy as a tree with the Resource count of each Folder, nested with --json")
+	flags.Bool("with-resources", false, "Also list the Resources in each Folder of the tree")
 }
 
 type folderListConfig struct {
@@ -40,6 +42,8 @@ type folderListConfig struct {
 	columnsChanged bool
 	jsonOutput     bool
 	celFilter      string
+	tree           bool
+	withResources  bool
 }
 
 func FolderList(cmd *cobra.Command, args []string) error {
@@ -71,6 +75,14 @@ func FolderList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if config.tree {
+		resources, err := getTreeResources(ctx, client, config.withResources)
+		if err != nil {
+			return err
+		}
+		return printFolderTree(buildFolderTree(folders, resources, config.withResources), config.jsonOutput)
+	}
+
 	if config.jsonOutput {
 		return printJSONFolders(folders, config.columnsChanged, config.columns)
 	}
@@ -178,6 +190,17 @@ func parseFolderListFlags(cmd *cobra.Command) (*folderListConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	tree, err := cmd.Flags().GetBool("tree")
+	if err != nil {
+		return nil, err
+	}
+	withResources, err := cmd.Flags().GetBool("with-resources")
+	if err != nil {
+		return nil, err
+	}
+	if withResources && !tree {
+		return nil, fmt.Errorf("--with-resources requires --tree")
+	}
 
 	return &folderListConfig{
 		search:         search,
@@ -186,5 +209,7 @@ func parseFolderListFlags(cmd *cobra.Command) (*folderListConfig, error) {
 		columnsChanged: cmd.Flags().Changed("column"),
 		jsonOutput:     jsonOutput,
 		celFilter:      celFilter,
+		tree:           tree,
+		withResources:  withResources,
 	}, nil
 }
@@ -1,9 +1,11 @@
 package folder
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,15 +14,18 @@ import (
 var FolderMoveCmd = &cobra.Command{
 	Use:   "folder",
 	Short: "Moves a Passbolt Folder into a Folder",
-	Long:  `Moves a Passbolt Folder into a Folder`,
+	Long:  `Moves a Passbolt Folder, or all Folders matching --filter after a Confirmation, into a Folder`,
 	RunE:  FolderMove,
 }
 
 func init() {
-	FolderMoveCmd.Flags().String("id", "", "id of Folder to Move")
-	FolderMoveCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to Move the Folder")
+	FolderMoveCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to Move")
+	FolderMoveCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to Move the Folder, by id or path")
+	FolderMoveCmd.Flags().String("filter", "", "CEL expression selecting the Folders to move instead of --id, see \"list folder --filter\"")
+	FolderMoveCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	FolderMoveCmd.MarkFlagRequired("id")
+	FolderMoveCmd.MarkFlagsOneRequired("id", "filter")
+	FolderMoveCmd.MarkFlagsMutuallyExclusive("id", "filter")
 	FolderMoveCmd.MarkFlagRequired("folderParentID")
 }
 
@@ -33,6 +38,14 @@ func FolderMove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -44,7 +57,43 @@ func FolderMove(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.MoveFolder(
+	resolver := util.NewResolver(client)
+	id, err = resolver.Folder(ctx, id)
+	if err != nil {
+		return err
+	}
+	folderParentID, err = resolver.Folder(ctx, folderParentID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectFolderTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Moved", "Folders", yes, func(id string) error {
+			return moveFolder(ctx, client, id, folderParentID)
+		})
+	}
+	return moveFolder(ctx, client, id, folderParentID)
+}
+
+func moveFolder(ctx context.Context, client *api.Client, id, folderParentID string) error {
+	if util.DryRun() {
+		folder, err := util.DescribeFolder(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		parent, err := util.DescribeFolder(ctx, client, folderParentID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("POST", fmt.Sprintf("/move/folder/%s.json", id), "move %v into %v", folder, parent)
+		return nil
+	}
+
+	err := helper.MoveFolder(
 		ctx,
 		client,
 		id,
@@ -1,9 +1,11 @@
 package folder
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,17 +14,20 @@ import (
 var FolderShareCmd = &cobra.Command{
 	Use:   "folder",
 	Short: "Shares a Passbolt Folder",
-	Long:  `Shares a Passbolt Folder`,
+	Long:  `Shares a Passbolt Folder, or all Folders matching --filter after a Confirmation`,
 	RunE:  FolderShare,
 }
 
 func init() {
-	FolderShareCmd.Flags().String("id", "", "id of Folder to Share")
+	FolderShareCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to Share")
 	FolderShareCmd.Flags().IntP("type", "t", 1, "Permission Type (1 Read Only, 7 Can Update, 15 Owner)")
-	FolderShareCmd.Flags().StringArrayP("user", "u", []string{}, "User id's to share with")
-	FolderShareCmd.Flags().StringArrayP("group", "g", []string{}, "Group id's to share with")
+	FolderShareCmd.Flags().StringArrayP("user", "u", []string{}, "Users (id or Username) to share with")
+	FolderShareCmd.Flags().StringArrayP("group", "g", []string{}, "Groups (id or name) to share with")
+	FolderShareCmd.Flags().String("filter", "", "CEL expression selecting the Folders to share instead of --id, see \"list folder --filter\"")
+	FolderShareCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	FolderShareCmd.MarkFlagRequired("id")
+	FolderShareCmd.MarkFlagsOneRequired("id", "filter")
+	FolderShareCmd.MarkFlagsMutuallyExclusive("id", "filter")
 	FolderShareCmd.MarkFlagRequired("type")
 }
 
@@ -43,6 +48,14 @@ func FolderShare(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -54,7 +67,47 @@ func FolderShare(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.ShareFolderWithUsersAndGroups(
+	resolver := util.NewResolver(client)
+	id, err = resolver.Folder(ctx, id)
+	if err != nil {
+		return err
+	}
+	users, err = resolver.Users(ctx, users)
+	if err != nil {
+		return err
+	}
+	groups, err = resolver.Groups(ctx, groups)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectFolderTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Shared", "Folders", yes, func(id string) error {
+			return shareFolder(ctx, client, id, users, groups, pType)
+		})
+	}
+	return shareFolder(ctx, client, id, users, groups, pType)
+}
+
+func shareFolder(ctx context.Context, client *api.Client, id string, users, groups []string, pType int) error {
+	if util.DryRun() {
+		folder, err := util.DescribeFolder(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		share, err := util.DescribeShare(ctx, client, users, groups, pType)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/share/folder/%s.json", id), "share %v with %v", folder, share)
+		return nil
+	}
+
+	err := helper.ShareFolderWithUsersAndGroups(
 		ctx,
 		client,
 		id,
@@ -0,0 +1,162 @@
+package folder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+// folderTreeNode is a Folder in the tree output, the root has no ID
+type folderTreeNode struct {
+	ID            string            `json:"id,omitempty"`
+	Name          string            `json:"name"`
+	ResourceCount int               `json:"resource_count"`
+	Folders       []*folderTreeNode `json:"folders"`
+	Resources     []folderTreeLeaf  `json:"resources,omitempty"`
+}
+
+// folderTreeLeaf is a Resource in the tree output
+type folderTreeLeaf struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// treeResource is what the tree needs to know about a Resource, Name is only
+// set with --with-resources
+type treeResource struct {
+	ID             string
+	FolderParentID string
+	Name           string
+}
+
+// getTreeResources lists the Resources for the tree, their names are only
+// decrypted if withNames is set
+func getTreeResources(ctx context.Context, client *api.Client, withNames bool) ([]treeResource, error) {
+	result := []treeResource{}
+	if !withNames {
+		resources, err := client.GetResources(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing Resource: %w", err)
+		}
+		for _, r := range resources {
+			result = append(result, treeResource{ID: r.ID, FolderParentID: r.FolderParentID})
+		}
+		return result, nil
+	}
+
+	resources, err := resource.GetDecryptedResources(ctx, client, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resources {
+		result = append(result, treeResource{
+			ID:             r.Resource.ID,
+			FolderParentID: r.Resource.FolderParentID,
+			Name:           helper.GetStringField(r.Metadata, "name"),
+		})
+	}
+	return result, nil
+}
+
+// buildFolderTree arranges folders below a root node. Folders whose parent is
+// not in folders, e.g. because of a filter, are shown below the root.
+func buildFolderTree(folders []api.Folder, resources []treeResource, withResources bool) *folderTreeNode {
+	root := &folderTreeNode{Name: "/", Folders: []*folderTreeNode{}}
+	nodes := make(map[string]*folderTreeNode, len(folders))
+	for _, f := range folders {
+		nodes[f.ID] = &folderTreeNode{ID: f.ID, Name: f.Name, Folders: []*folderTreeNode{}}
+	}
+	for _, f := range folders {
+		parent, ok := nodes[f.FolderParentID]
+		// a Folder can't be its own parent, guard against malformed responses
+		if !ok || f.FolderParentID == f.ID {
+			parent = root
+		}
+		parent.Folders = append(parent.Folders, nodes[f.ID])
+	}
+
+	for _, r := range resources {
+		node := root
+		if r.FolderParentID != "" {
+			var ok bool
+			node, ok = nodes[r.FolderParentID]
+			if !ok {
+				continue
+			}
+		}
+		node.ResourceCount++
+		if withResources {
+			node.Resources = append(node.Resources, folderTreeLeaf{ID: r.ID, Name: r.Name})
+		}
+	}
+
+	sortFolderTree(root)
+	return root
+}
+
+func sortFolderTree(node *folderTreeNode) {
+	sort.SliceStable(node.Folders, func(i, j int) bool {
+		return node.Folders[i].Name < node.Folders[j].Name
+	})
+	sort.SliceStable(node.Resources, func(i, j int) bool {
+		return node.Resources[i].Name < node.Resources[j].Name
+	})
+	for _, child := range node.Folders {
+		sortFolderTree(child)
+	}
+}
+
+// renderFolderTree renders the tree like tree(1), Folders first and then
+// Resources, each Folder with its Resource count
+func renderFolderTree(root *folderTreeNode) string {
+	var b strings.Builder
+	b.WriteString(folderTreeLabel(root) + "\n")
+	renderFolderTreeChildren(&b, root, "")
+	return b.String()
+}
+
+func renderFolderTreeChildren(b *strings.Builder, node *folderTreeNode, prefix string) {
+	total := len(node.Folders) + len(node.Resources)
+	for i := 0; i < total; i++ {
+		connector, indent := "├── ", "│   "
+		if i == total-1 {
+			connector, indent = "└── ", "    "
+		}
+		if i < len(node.Folders) {
+			child := node.Folders[i]
+			b.WriteString(prefix + connector + folderTreeLabel(child) + "\n")
+			renderFolderTreeChildren(b, child, prefix+indent)
+			continue
+		}
+		leaf := node.Resources[i-len(node.Folders)]
+		b.WriteString(prefix + connector + shellescape.StripUnsafe(leaf.Name) + "\n")
+	}
+}
+
+func folderTreeLabel(node *folderTreeNode) string {
+	unit := "Resources"
+	if node.ResourceCount == 1 {
+		unit = "Resource"
+	}
+	return fmt.Sprintf("%v (%v %v)", shellescape.StripUnsafe(node.Name), node.ResourceCount, unit)
+}
+
+func printFolderTree(root *folderTreeNode, jsonOutput bool) error {
+	if jsonOutput {
+		out, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	fmt.Print(renderFolderTree(root))
+	return nil
+}
@@ -0,0 +1,44 @@
+package folder
+
+import (
+	"testing"
+
+	"github.com/passbolt/go-passbolt/api"
+)
+
+func TestBuildFolderTree(t *testing.T) {
+	folders := []api.Folder{
+		{ID: "f2", Name: "Staging"},
+		{ID: "f1", Name: "Prod"},
+		{ID: "f3", FolderParentID: "f1", Name: "Web"},
+		{ID: "f4", FolderParentID: "f1", Name: "Databases"},
+		// the parent is not in the list, e.g. because of --filter
+		{ID: "f5", FolderParentID: "missing", Name: "Orphan"},
+	}
+	resources := []treeResource{
+		{ID: "r1", Name: "root-resource"},
+		{ID: "r2", FolderParentID: "f4", Name: "postgres"},
+		{ID: "r3", FolderParentID: "f4", Name: "mysql"},
+		{ID: "r4", FolderParentID: "missing", Name: "hidden"},
+	}
+
+	root := buildFolderTree(folders, resources, true)
+	want := `/ (1 Resource)
+├── Orphan (0 Resources)
+├── Prod (0 Resources)
+│   ├── Databases (2 Resources)
+│   │   ├── mysql
+│   │   └── postgres
+│   └── Web (0 Resources)
+├── Staging (0 Resources)
+└── root-resource
+`
+	if got := renderFolderTree(root); got != want {
+		t.Errorf("unexpected tree:\n%v\nwant:\n%v", got, want)
+	}
+
+	root = buildFolderTree(folders, resources, false)
+	if len(root.Resources) != 0 || root.ResourceCount != 1 {
+		t.Errorf("resources listed without withResources: %+v", root)
+	}
+}
@@ -12,12 +12,13 @@ import (
 var FolderUpdateCmd = &cobra.Command{
 	Use:   "folder",
 	Short: "Updates a Passbolt Folder",
-	Long:  `Updates a Passbolt Folder`,
-	RunE:  FolderUpdate,
+	Long: `Updates a Passbolt Folder. Unlike the other update Commands it takes no --filter,
+as Folders are only renamed and several Folders would get the same Name.`,
+	RunE: FolderUpdate,
 }
 
 func init() {
-	FolderUpdateCmd.Flags().String("id", "", "id of Folder to Update")
+	FolderUpdateCmd.Flags().String("id", "", "id or path (/Parent/Name) of Folder to Update")
 	FolderUpdateCmd.Flags().StringP("name", "n", "", "Folder Name")
 
 	FolderUpdateCmd.MarkFlagRequired("id")
@@ -44,6 +45,20 @@ func FolderUpdate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).Folder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if util.DryRun() {
+		folder, err := util.DescribeFolder(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/folders/%s.json", id), "rename %v to %q", folder, name)
+		return nil
+	}
+
 	err = helper.UpdateFolder(
 		ctx,
 		client,
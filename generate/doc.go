@@ -0,0 +1,2 @@
+// Package generate implements generating passwords and passphrases.
+package generate
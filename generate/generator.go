@@ -0,0 +1,174 @@
+package generate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// generatorFlags are the flags added by AddGeneratorFlags without their prefix
+var generatorFlags = []string{"type", "length", "uppercase", "lowercase", "digits", "symbols", "emoji", "exclude", "exclude-look-alike", "words", "separator", "word-case", "wordlist"}
+
+// AddGeneratorFlags adds the flags selecting what to generate, each name is
+// prefixed with prefix so commands can avoid clashes with their own flags
+func AddGeneratorFlags(flags *pflag.FlagSet, prefix string) {
+	flags.String(prefix+"type", "", "What to generate, password or passphrase (default from the Password Policy)")
+	flags.Int(prefix+"length", 0, "Length of the Password (default from the Password Policy)")
+	flags.Bool(prefix+"uppercase", true, "Use uppercase Letters")
+	flags.Bool(prefix+"lowercase", true, "Use lowercase Letters")
+	flags.Bool(prefix+"digits", true, "Use Digits")
+	flags.Bool(prefix+"symbols", true, "Use Symbols and Parentheses")
+	flags.Bool(prefix+"emoji", false, "Use Emoji")
+	flags.String(prefix+"exclude", "", "Characters not to use")
+	flags.Bool(prefix+"exclude-look-alike", true, "Don't use look-alike Characters like O and 0")
+	flags.Int(prefix+"words", 0, "Number of Words of the Passphrase (default from the Password Policy)")
+	flags.String(prefix+"separator", "", "Separator between the Words of the Passphrase (default from the Password Policy)")
+	flags.String(prefix+"word-case", "", "Case of the Words, lowercase, uppercase or camelcase (default from the Password Policy)")
+	flags.String(prefix+"wordlist", "", "File with one Word per Line to build Passphrases from")
+}
+
+// ChangedGeneratorFlag returns the name of a generator flag that was set, or
+// an empty string if none was
+func ChangedGeneratorFlag(cmd *cobra.Command, prefix string) string {
+	for _, flag := range generatorFlags {
+		if cmd.Flags().Changed(prefix + flag) {
+			return prefix + flag
+		}
+	}
+	return ""
+}
+
+// Generate generates a Password or Passphrase from the settings of policy
+// overridden by the generator flags. It returns what it generated, password
+// or passphrase. With checkPolicy settings outside of the policy limits are
+// rejected.
+func Generate(cmd *cobra.Command, prefix string, policy util.PasswordPolicy, checkPolicy bool) (string, string, error) {
+	kind, err := cmd.Flags().GetString(prefix + "type")
+	if err != nil {
+		return "", "", err
+	}
+	exclude, err := cmd.Flags().GetString(prefix + "exclude")
+	if err != nil {
+		return "", "", err
+	}
+	wordlistPath, err := cmd.Flags().GetString(prefix + "wordlist")
+	if err != nil {
+		return "", "", err
+	}
+
+	if kind == "" {
+		kind = policy.DefaultGenerator
+	}
+	var result string
+	switch kind {
+	case "password":
+		settings := policy.PasswordGeneratorSettings
+		err = applyPasswordFlags(cmd, prefix, &settings)
+		if err != nil {
+			return "", "", err
+		}
+		if checkPolicy {
+			err = settings.Check()
+			if err != nil {
+				return "", "", err
+			}
+		}
+		result, err = util.GeneratePassword(settings, exclude)
+	case "passphrase":
+		settings := policy.PassphraseGeneratorSettings
+		err = applyPassphraseFlags(cmd, prefix, &settings)
+		if err != nil {
+			return "", "", err
+		}
+		if checkPolicy {
+			err = settings.Check()
+			if err != nil {
+				return "", "", err
+			}
+		}
+		if wordlistPath == "" {
+			return "", "", fmt.Errorf("generating a Passphrase requires a word list, set it with --%vwordlist", prefix)
+		}
+		var wordlist []string
+		wordlist, err = readWordlist(wordlistPath)
+		if err != nil {
+			return "", "", err
+		}
+		result, err = util.GeneratePassphrase(settings, wordlist)
+	default:
+		return "", "", fmt.Errorf("unknown %vtype %q, must be password or passphrase", prefix, kind)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("generating %v: %w", kind, err)
+	}
+	return result, kind, nil
+}
+
+// applyPasswordFlags overrides the settings with the flags that were set
+func applyPasswordFlags(cmd *cobra.Command, prefix string, s *util.PasswordGeneratorSettings) error {
+	if cmd.Flags().Changed(prefix + "length") {
+		length, err := cmd.Flags().GetInt(prefix + "length")
+		if err != nil {
+			return err
+		}
+		s.Length = length
+	}
+	for flag, masks := range map[string][]*bool{
+		"uppercase":          {&s.MaskUpper},
+		"lowercase":          {&s.MaskLower},
+		"digits":             {&s.MaskDigit},
+		"symbols":            {&s.MaskParenthesis, &s.MaskChar1, &s.MaskChar2, &s.MaskChar3, &s.MaskChar4, &s.MaskChar5},
+		"emoji":              {&s.MaskEmoji},
+		"exclude-look-alike": {&s.ExcludeLookAlikeChars},
+	} {
+		if !cmd.Flags().Changed(prefix + flag) {
+			continue
+		}
+		enabled, err := cmd.Flags().GetBool(prefix + flag)
+		if err != nil {
+			return err
+		}
+		for _, mask := range masks {
+			*mask = enabled
+		}
+	}
+	return nil
+}
+
+// applyPassphraseFlags overrides the settings with the flags that were set
+func applyPassphraseFlags(cmd *cobra.Command, prefix string, s *util.PassphraseGeneratorSettings) error {
+	if cmd.Flags().Changed(prefix + "words") {
+		words, err := cmd.Flags().GetInt(prefix + "words")
+		if err != nil {
+			return err
+		}
+		s.Words = words
+	}
+	if cmd.Flags().Changed(prefix + "separator") {
+		separator, err := cmd.Flags().GetString(prefix + "separator")
+		if err != nil {
+			return err
+		}
+		s.WordSeparator = separator
+	}
+	if cmd.Flags().Changed(prefix + "word-case") {
+		wordCase, err := cmd.Flags().GetString(prefix + "word-case")
+		if err != nil {
+			return err
+		}
+		s.WordCase = wordCase
+	}
+	return nil
+}
+
+func readWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening word list: %w", err)
+	}
+	defer file.Close()
+	return util.ReadWordlist(file)
+}
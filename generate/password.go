@@ -0,0 +1,60 @@
+package generate
+
+import (
+	"fmt"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+)
+
+// GeneratePasswordCmd Generates a Password or Passphrase
+var GeneratePasswordCmd = &cobra.Command{
+	Use:   "password",
+	Short: "Generates a Password or Passphrase",
+	Long: `Generates a random Password or Passphrase and prints it.
+
+By default the Password Policy of the Organization is fetched from the Server and used as the starting point,
+the Flags override single settings of it. Lengths and word counts outside of the limits of the Policy are rejected.
+With --policy=false the Passbolt defaults are used and no Login is required.
+
+Passphrases are built from a word list with one word per line, e.g. the EFF large word list, given with --wordlist.
+"create resource" and "update resource" take the same Flags prefixed with --generate-.`,
+	Args: cobra.NoArgs,
+	RunE: GeneratePassword,
+}
+
+func init() {
+	GeneratePasswordCmd.Flags().Bool("policy", true, "Use the Password Policy of the Server")
+	AddGeneratorFlags(GeneratePasswordCmd.Flags(), "")
+}
+
+func GeneratePassword(cmd *cobra.Command, args []string) error {
+	usePolicy, err := cmd.Flags().GetBool("policy")
+	if err != nil {
+		return err
+	}
+
+	policy := util.DefaultPasswordPolicy
+	if usePolicy {
+		ctx, cancel := util.GetContext()
+		defer cancel()
+
+		client, err := util.GetClient(ctx)
+		if err != nil {
+			return err
+		}
+		policy, err = util.GetPasswordPolicy(ctx, client)
+		util.SaveSessionKeysAndLogout(ctx, client)
+		if err != nil {
+			return err
+		}
+	}
+	cmd.SilenceUsage = true
+
+	result, _, err := Generate(cmd, "", policy, usePolicy)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
@@ -20,8 +20,8 @@ var GroupCreateCmd = &cobra.Command{
 func init() {
 	GroupCreateCmd.Flags().StringP("name", "n", "", "Group Name")
 
-	GroupCreateCmd.Flags().StringArrayP("user", "u", []string{}, "Users to Add to Group")
-	GroupCreateCmd.Flags().StringArrayP("manager", "m", []string{}, "Managers to Add to Group (atleast 1 is required)")
+	GroupCreateCmd.Flags().StringArrayP("user", "u", []string{}, "Users to Add to Group, by id or Username")
+	GroupCreateCmd.Flags().StringArrayP("manager", "m", []string{}, "Managers to Add to Group, by id or Username (atleast 1 is required)")
 
 	GroupCreateCmd.MarkFlagRequired("name")
 	GroupCreateCmd.MarkFlagRequired("manager")
@@ -69,6 +69,23 @@ func GroupCreate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	resolver := util.NewResolver(client)
+	for i := range ops {
+		ops[i].UserID, err = resolver.User(ctx, ops[i].UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if util.DryRun() {
+		members, err := describeMembershipOperations(ctx, client, ops)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("POST", "/groups.json", "create Group %q with %v", name, members)
+		return nil
+	}
+
 	id, err := helper.CreateGroup(
 		ctx,
 		client,
@@ -1,9 +1,11 @@
 package group
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/spf13/cobra"
 )
 
@@ -11,19 +13,35 @@ import (
 var GroupDeleteCmd = &cobra.Command{
 	Use:   "group",
 	Short: "Deletes a Passbolt Group",
-	Long:  `Deletes a Passbolt Group`,
+	Long:  `Deletes a Passbolt Group, or all Groups matching --filter after a Confirmation`,
 	RunE:  GroupDelete,
 }
 
+func init() {
+	GroupDeleteCmd.Flags().String("filter", "", "CEL expression selecting the Groups to delete instead of --id, see \"list group --filter\"")
+	GroupDeleteCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
+}
+
 func GroupDelete(cmd *cobra.Command, args []string) error {
 	resourceID, err := cmd.Flags().GetString("id")
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
-	if resourceID == "" {
+	if resourceID == "" && filter == "" {
 		return fmt.Errorf("no ID to Delete Provided")
 	}
+	if resourceID != "" && filter != "" {
+		return fmt.Errorf("--id can't be used with --filter")
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -35,7 +53,34 @@ func GroupDelete(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = client.DeleteGroup(ctx, resourceID)
+	resourceID, err = util.NewResolver(client).Group(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectGroupTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Deleted", "Groups", yes, func(id string) error {
+			return deleteGroup(ctx, client, id)
+		})
+	}
+	return deleteGroup(ctx, client, resourceID)
+}
+
+func deleteGroup(ctx context.Context, client *api.Client, resourceID string) error {
+	if util.DryRun() {
+		group, err := util.DescribeGroup(ctx, client, resourceID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("DELETE", fmt.Sprintf("/groups/%s.json", resourceID), "delete %v", group)
+		return nil
+	}
+
+	err := client.DeleteGroup(ctx, resourceID)
 	if err != nil {
 		return fmt.Errorf("deleting Group: %w", err)
 	}
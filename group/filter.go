@@ -52,3 +52,20 @@ func filterGroups(groups *[]api.Group, celCmd string, ctx context.Context) ([]ap
 
 	return filteredGroups, nil
 }
+
+// selectGroupTargets returns the Groups matching a CEL expression for a bulk operation
+func selectGroupTargets(ctx context.Context, client *api.Client, celCmd string) ([]util.BulkTarget, error) {
+	groups, err := client.GetGroups(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing Group: %w", err)
+	}
+	groups, err = filterGroups(&groups, celCmd, ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]util.BulkTarget, len(groups))
+	for i, g := range groups {
+		targets[i] = util.BulkTarget{ID: g.ID, Description: g.Name}
+	}
+	return targets, nil
+}
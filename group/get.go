@@ -52,6 +52,11 @@ func GroupGet(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).Group(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	name, memberships, err := helper.GetGroup(
 		ctx,
 		client,
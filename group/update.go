@@ -1,9 +1,11 @@
 package group
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,8 +14,9 @@ import (
 var GroupUpdateCmd = &cobra.Command{
 	Use:   "group",
 	Short: "Updates a Passbolt Group",
-	Long:  `Updates a Passbolt Group`,
-	RunE:  GroupUpdate,
+	Long: `Updates a Passbolt Group, or the Memberships of all Groups matching --filter after a Confirmation.
+Groups can't be renamed with --filter as their Names must be unique.`,
+	RunE: GroupUpdate,
 }
 
 func init() {
@@ -24,8 +27,12 @@ func init() {
 
 	GroupUpdateCmd.Flags().StringArrayP("user", "u", []string{}, "Users to Add/Remove to/from Group(Including Group Managers)")
 	GroupUpdateCmd.Flags().StringArrayP("manager", "m", []string{}, "Managers to Add/Remove to/from Group")
+	GroupUpdateCmd.Flags().String("filter", "", "CEL expression selecting the Groups to update instead of --id, see \"list group --filter\"")
+	GroupUpdateCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	GroupUpdateCmd.MarkFlagRequired("id")
+	GroupUpdateCmd.MarkFlagsOneRequired("id", "filter")
+	GroupUpdateCmd.MarkFlagsMutuallyExclusive("id", "filter")
+	GroupUpdateCmd.MarkFlagsMutuallyExclusive("name", "filter")
 }
 
 func GroupUpdate(cmd *cobra.Command, args []string) error {
@@ -49,6 +56,14 @@ func GroupUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ops := []helper.GroupMembershipOperation{}
 	for _, user := range users {
@@ -76,7 +91,49 @@ func GroupUpdate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.UpdateGroup(
+	resolver := util.NewResolver(client)
+	id, err = resolver.Group(ctx, id)
+	if err != nil {
+		return err
+	}
+	for i := range ops {
+		ops[i].UserID, err = resolver.User(ctx, ops[i].UserID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if filter != "" {
+		targets, err := selectGroupTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Updated", "Groups", yes, func(id string) error {
+			return updateGroup(ctx, client, id, name, ops)
+		})
+	}
+	return updateGroup(ctx, client, id, name, ops)
+}
+
+func updateGroup(ctx context.Context, client *api.Client, id, name string, ops []helper.GroupMembershipOperation) error {
+	if util.DryRun() {
+		group, err := util.DescribeGroup(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		members, err := describeMembershipOperations(ctx, client, ops)
+		if err != nil {
+			return err
+		}
+		if name != "" {
+			util.PrintDryRun("PUT", fmt.Sprintf("/groups/%s.json", id), "rename %v to %q and change %v", group, name, members)
+		} else {
+			util.PrintDryRun("PUT", fmt.Sprintf("/groups/%s.json", id), "change %v of %v", members, group)
+		}
+		return nil
+	}
+
+	err := helper.UpdateGroup(
 		ctx,
 		client,
 		id,
@@ -88,3 +145,24 @@ func GroupUpdate(cmd *cobra.Command, args []string) error {
 	}
 	return nil
 }
+
+// describeMembershipOperations describes membership changes for dry-run
+// output, resolving each User.
+func describeMembershipOperations(ctx context.Context, client *api.Client, ops []helper.GroupMembershipOperation) (string, error) {
+	changes := []string{}
+	for _, op := range ops {
+		user, err := util.DescribeUser(ctx, client, op.UserID)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case op.Delete:
+			changes = append(changes, "remove "+user)
+		case op.IsGroupManager:
+			changes = append(changes, "add "+user+" as Manager")
+		default:
+			changes = append(changes, "add "+user+" as Member")
+		}
+	}
+	return fmt.Sprintf("Memberships %v", changes), nil
+}
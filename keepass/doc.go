@@ -1,2 +1,2 @@
-// Package keepass implements KeePass export functionality.
+// Package keepass implements KeePass export and import functionality.
 package keepass
@@ -2,11 +2,8 @@ package keepass
 
 import (
 	"fmt"
-	"net/url"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
 
 	"github.com/passbolt/go-passbolt-cli/util"
 	"github.com/passbolt/go-passbolt/api"
@@ -30,6 +27,7 @@ func init() {
 	KeepassExportCmd.Flags().StringP("file", "f", "passbolt-export.kdbx", "File name of the KeePass File")
 	KeepassExportCmd.Flags().StringP("password", "p", "", "Password for the KeePass File, if empty prompts interactively")
 	KeepassExportCmd.Flags().String("kdbx-version", "v3", "KDBX format version: v3 (AES-KDF, KDBX 3.1) or v4 (Argon2, KDBX 4)")
+	KeepassExportCmd.Flags().Bool("flat", false, "Put all Entries into a single Group instead of reproducing the Folder hierarchy")
 }
 
 func KeepassExport(cmd *cobra.Command, args []string) error {
@@ -52,6 +50,11 @@ func KeepassExport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	flat, err := cmd.Flags().GetBool("flat")
+	if err != nil {
+		return err
+	}
+
 	var kdbxVersion gokeepasslib.DatabaseOption
 	switch kdbxVersionFlag {
 	case "v3":
@@ -92,15 +95,21 @@ func KeepassExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("getting Resources: %w", err)
 	}
 
+	var folders []api.Folder
+	if !flat {
+		fmt.Println("Getting Folders...")
+		folders, err = client.GetFolders(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("getting Folders: %w", err)
+		}
+	}
+
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("creating File: %w", err)
 	}
 	defer file.Close()
 
-	rootGroup := gokeepasslib.NewGroup()
-	rootGroup.Name = "root"
-
 	pterm.EnableStyling()
 	pterm.DisableColor()
 	progressbar, err := pterm.DefaultProgressbar.WithTitle("Decryping Resources").WithTotal(len(resources)).Start()
@@ -108,6 +117,7 @@ func KeepassExport(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("progress: %w", err)
 	}
 
+	entriesByFolder := map[string][]gokeepasslib.Entry{}
 	for _, resource := range resources {
 		entry, err := getKeepassEntry(client, resource, resource.Secrets[0], resource.ResourceType)
 		if err != nil {
@@ -116,10 +126,16 @@ func KeepassExport(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		rootGroup.Entries = append(rootGroup.Entries, *entry)
+		folderID := resource.FolderParentID
+		if flat {
+			folderID = ""
+		}
+		entriesByFolder[folderID] = append(entriesByFolder[folderID], *entry)
 		progressbar.Increment()
 	}
 
+	rootGroup := buildKeepassGroupTree(folders, entriesByFolder)
+
 	db := gokeepasslib.NewDatabase(kdbxVersion)
 	db.Content.Meta.DatabaseName = "Passbolt Export"
 
@@ -144,6 +160,55 @@ func KeepassExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// buildKeepassGroupTree reproduces the Folder hierarchy as nested Groups below
+// a single "root" Group. Entries whose Folder is unknown (e.g. not shared with
+// us, or when folders is empty for a flat export) are placed in the root Group.
+func buildKeepassGroupTree(folders []api.Folder, entriesByFolder map[string][]gokeepasslib.Entry) gokeepasslib.Group {
+	known := make(map[string]bool, len(folders))
+	for _, folder := range folders {
+		known[folder.ID] = true
+	}
+
+	children := map[string][]api.Folder{}
+	for _, folder := range folders {
+		parentID := folder.FolderParentID
+		if !known[parentID] {
+			parentID = ""
+		}
+		children[parentID] = append(children[parentID], folder)
+	}
+	for id := range children {
+		sort.Slice(children[id], func(i, j int) bool {
+			return children[id][i].Name < children[id][j].Name
+		})
+	}
+
+	var build func(id, name string) gokeepasslib.Group
+	build = func(id, name string) gokeepasslib.Group {
+		group := gokeepasslib.NewGroup()
+		group.Name = name
+		group.Entries = entriesByFolder[id]
+		for _, child := range children[id] {
+			group.Groups = append(group.Groups, build(child.ID, child.Name))
+		}
+		return group
+	}
+
+	orphans := []string{}
+	for id := range entriesByFolder {
+		if id != "" && !known[id] {
+			orphans = append(orphans, id)
+		}
+	}
+	sort.Strings(orphans)
+
+	rootGroup := build("", "root")
+	for _, id := range orphans {
+		rootGroup.Entries = append(rootGroup.Entries, entriesByFolder[id]...)
+	}
+	return rootGroup
+}
+
 func getKeepassEntry(client *api.Client, resource api.Resource, secret api.Secret, rType api.ResourceType) (*gokeepasslib.Entry, error) {
 	_, metadata, secretFields, err := helper.GetResourceFieldMaps(client, resource, secret, rType, true)
 	if err != nil {
@@ -167,43 +232,18 @@ func getKeepassEntry(client *api.Client, resource api.Resource, secret api.Secre
 	)
 
 	if totpRaw, ok := secretFields["totp"].(map[string]any); ok {
-		secretKey, _ := totpRaw["secret_key"].(string)
+		issuer := uri
+		if uri == "" {
+			issuer = name
+		}
+		accountName := username
+		if username == "" {
+			accountName = name
+		}
+
 		// Skip TOTP entry if secret_key is missing — can't build a valid OTP URI
-		if secretKey != "" {
-			algorithm, _ := totpRaw["algorithm"].(string)
-			var digits, period int
-			if d, ok := totpRaw["digits"].(float64); ok {
-				digits = int(d)
-			}
-			if p, ok := totpRaw["period"].(float64); ok {
-				period = int(p)
-			}
-
-			v := url.Values{}
-			v.Set("secret", secretKey)
-			v.Set("period", strconv.FormatUint(uint64(period), 10))
-			v.Set("algorithm", algorithm)
-			v.Set("digits", fmt.Sprint(digits))
-
-			issuer := uri
-			if uri == "" {
-				issuer = name
-			}
-			v.Set("issuer", issuer)
-
-			accountName := username
-			if username == "" {
-				accountName = name
-			}
-
-			u := url.URL{
-				Scheme:   "otpauth",
-				Host:     "totp",
-				Path:     "/" + issuer + ":" + accountName,
-				RawQuery: encodeQuery(v),
-			}
-
-			entry.Values = append(entry.Values, gokeepasslib.ValueData{Key: "otp", Value: gokeepasslib.V{Content: u.String(), Protected: w.NewBoolWrapper(true)}})
+		if otp := util.TOTPURI(totpRaw, issuer, accountName); otp != "" {
+			entry.Values = append(entry.Values, gokeepasslib.ValueData{Key: "otp", Value: gokeepasslib.V{Content: otp, Protected: w.NewBoolWrapper(true)}})
 		}
 	}
 
@@ -217,64 +257,10 @@ func getKeepassEntry(client *api.Client, resource api.Resource, secret api.Secre
 }
 
 func addCustomFields(entry *gokeepasslib.Entry, metadata, secretFields map[string]any) {
-	metaList, _ := metadata["custom_fields"].([]any)
-	if len(metaList) == 0 {
-		return
-	}
-	secretList, _ := secretFields["custom_fields"].([]any)
-	valueByID := make(map[string]string, len(secretList))
-	for _, item := range secretList {
-		m, ok := item.(map[string]any)
-		if !ok {
-			continue
-		}
-		id, _ := m["id"].(string)
-		val, _ := m["secret_value"].(string)
-		if id != "" {
-			valueByID[id] = val
-		}
-	}
-	for _, item := range metaList {
-		m, ok := item.(map[string]any)
-		if !ok {
-			continue
-		}
-		id, _ := m["id"].(string)
-		key, _ := m["metadata_key"].(string)
-		if key == "" {
-			continue
-		}
+	for _, field := range util.CustomFields(metadata, secretFields) {
 		entry.Values = append(entry.Values, gokeepasslib.ValueData{
-			Key:   key,
-			Value: gokeepasslib.V{Content: valueByID[id], Protected: w.NewBoolWrapper(true)},
+			Key:   field.Key,
+			Value: gokeepasslib.V{Content: field.Value, Protected: w.NewBoolWrapper(true)},
 		})
 	}
 }
-
-// EncodeQuery is a copy-paste of url.Values.Encode, except it uses %20 instead
-// of + to encode spaces. This is necessary to correctly render spaces in some
-// authenticator apps, like Google Authenticator.
-func encodeQuery(v url.Values) string {
-	if v == nil {
-		return ""
-	}
-	var buf strings.Builder
-	keys := make([]string, 0, len(v))
-	for k := range v {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		vs := v[k]
-		keyEscaped := url.PathEscape(k) // changed from url.QueryEscape
-		for _, v := range vs {
-			if buf.Len() > 0 {
-				buf.WriteByte('&')
-			}
-			buf.WriteString(keyEscaped)
-			buf.WriteByte('=')
-			buf.WriteString(url.PathEscape(v)) // changed from url.QueryEscape
-		}
-	}
-	return buf.String()
-}
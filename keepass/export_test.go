@@ -1,80 +1,50 @@
 package keepass
 
 import (
-	"net/url"
-	"strings"
 	"testing"
-)
 
-// encodeQuery is a copy of url.Values.Encode that uses %20 instead of '+' for
-// spaces, so authenticator apps (Google Authenticator) parse otpauth URIs
-// correctly. These tests lock in that distinction.
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/tobischo/gokeepasslib/v3"
+)
 
-func TestEncodeQuery_Empty(t *testing.T) {
-	if got := encodeQuery(nil); got != "" {
-		t.Errorf("nil input = %q, want empty", got)
-	}
-	if got := encodeQuery(url.Values{}); got != "" {
-		t.Errorf("empty input = %q, want empty", got)
+func TestBuildKeepassGroupTree_Nested(t *testing.T) {
+	folders := []api.Folder{
+		{ID: "b", Name: "Databases", FolderParentID: "a"},
+		{ID: "a", Name: "Prod"},
+		{ID: "c", Name: "Dev"},
 	}
-}
-
-func TestEncodeQuery_SpacesUsePercent20(t *testing.T) {
-	v := url.Values{"issuer": {"My Service"}}
-	got := encodeQuery(v)
-	if !strings.Contains(got, "%20") {
-		t.Errorf("encodeQuery should encode space as %%20, got %q", got)
+	entry := func(title string) gokeepasslib.Entry {
+		e := gokeepasslib.NewEntry()
+		e.Values = append(e.Values, gokeepasslib.ValueData{Key: "Title", Value: gokeepasslib.V{Content: title}})
+		return e
 	}
-	if strings.Contains(got, "+") {
-		t.Errorf("encodeQuery must not use + for spaces, got %q", got)
+	entries := map[string][]gokeepasslib.Entry{
+		"":  {entry("top")},
+		"b": {entry("postgres")},
 	}
-}
 
-func TestEncodeQuery_MatchesStdlibAfterPlusSubstitution(t *testing.T) {
-	// For values with no plus characters, encodeQuery's output should be
-	// identical to url.Values.Encode after replacing '+' with '%20'.
-	v := url.Values{
-		"issuer": {"Acme"},
-		"period": {"30"},
-		"digits": {"6"},
+	root := buildKeepassGroupTree(folders, entries)
+	if root.Name != "root" || len(root.Entries) != 1 || root.Entries[0].GetTitle() != "top" {
+		t.Fatalf("root = %q with %d entries", root.Name, len(root.Entries))
 	}
-	got := encodeQuery(v)
-	std := strings.ReplaceAll(v.Encode(), "+", "%20")
-	if got != std {
-		t.Errorf("encodeQuery(%v) = %q, want %q", v, got, std)
+	if len(root.Groups) != 2 || root.Groups[0].Name != "Dev" || root.Groups[1].Name != "Prod" {
+		t.Fatalf("top level groups not sorted by name: %+v", root.Groups)
 	}
-}
-
-func TestEncodeQuery_KeysAreSorted(t *testing.T) {
-	v := url.Values{
-		"zeta":  {"z"},
-		"alpha": {"a"},
-		"mu":    {"m"},
-	}
-	got := encodeQuery(v)
-	if !strings.HasPrefix(got, "alpha=a&") || !strings.HasSuffix(got, "&zeta=z") {
-		t.Errorf("expected keys in lexicographic order, got %q", got)
+	prod := root.Groups[1]
+	if len(prod.Groups) != 1 || prod.Groups[0].Name != "Databases" {
+		t.Fatalf("Prod should contain Databases, got %+v", prod.Groups)
 	}
-}
-
-func TestEncodeQuery_MultiValueRetained(t *testing.T) {
-	v := url.Values{"tag": {"foo", "bar"}}
-	got := encodeQuery(v)
-	if got != "tag=foo&tag=bar" {
-		t.Errorf("multi-value encoding = %q, want tag=foo&tag=bar", got)
+	if len(prod.Groups[0].Entries) != 1 || prod.Groups[0].Entries[0].GetTitle() != "postgres" {
+		t.Errorf("Databases entries = %+v", prod.Groups[0].Entries)
 	}
 }
 
-func TestEncodeQuery_DoesNotEscapeAmpersandOrEquals(t *testing.T) {
-	// encodeQuery uses url.PathEscape (not QueryEscape) to keep %20 for
-	// spaces (required by Google Authenticator). PathEscape does NOT escape
-	// '&' or '=', so values containing them produce an ambiguous query
-	// string. Acceptable for otpauth labels in practice (they don't contain
-	// either character) but a sharp edge — this test locks in current
-	// behavior so a future change to QueryEscape is a deliberate decision.
-	v := url.Values{"label": {"foo&bar=baz"}}
-	got := encodeQuery(v)
-	if got != "label=foo&bar=baz" {
-		t.Errorf("encodeQuery(%v) = %q, want label=foo&bar=baz (current behavior)", v, got)
+func TestBuildKeepassGroupTree_UnknownFolderFallsBackToRoot(t *testing.T) {
+	// Resources can live in folders that aren't visible to us (or the export
+	// is flat and no folders were fetched); those entries must not be lost.
+	e := gokeepasslib.NewEntry()
+	root := buildKeepassGroupTree(nil, map[string][]gokeepasslib.Entry{"missing": {e}})
+	if len(root.Groups) != 0 || len(root.Entries) != 1 {
+		t.Errorf("root = %d groups / %d entries, want 0 / 1", len(root.Groups), len(root.Entries))
 	}
 }
@@ -0,0 +1,212 @@
+package keepass
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"github.com/tobischo/gokeepasslib/v3"
+)
+
+// KeepassImportCmd Imports a KeePass File into Passbolt
+var KeepassImportCmd = &cobra.Command{
+	Use:     "keepass",
+	Short:   "Imports a KeePass File into Passbolt",
+	Long:    `Imports a KeePass File into Passbolt. Groups are recreated as Folders and Entries as Resources`,
+	Aliases: []string{},
+	RunE:    KeepassImport,
+}
+
+func init() {
+	KeepassImportCmd.Flags().StringP("file", "f", "", "File name of the KeePass File")
+	KeepassImportCmd.Flags().StringP("password", "p", "", "Password for the KeePass File, if empty prompts interactively")
+	KeepassImportCmd.Flags().String("folderParentID", "", "Folder in which to import, defaults to the root")
+
+	KeepassImportCmd.MarkFlagRequired("file")
+}
+
+// keepassStandardKeys are the entry fields mapped onto regular resource fields,
+// everything else is imported as a custom field.
+var keepassStandardKeys = map[string]bool{
+	"Title":    true,
+	"UserName": true,
+	"URL":      true,
+	"Password": true,
+	"Notes":    true,
+	"otp":      true,
+}
+
+type keepassImportStats struct {
+	folders   int
+	resources int
+	skipped   int
+}
+
+func KeepassImport(cmd *cobra.Command, args []string) error {
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("the Filename cannot be empty")
+	}
+
+	keepassPassword, err := cmd.Flags().GetString("password")
+	if err != nil {
+		return err
+	}
+
+	folderParentID, err := cmd.Flags().GetString("folderParentID")
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("opening File: %w", err)
+	}
+	defer file.Close()
+
+	if keepassPassword == "" {
+		pw, err := util.ReadPassword("Enter KeePass Password:")
+		if err != nil {
+			fmt.Println()
+			return fmt.Errorf("reading KeePass Password: %w", err)
+		}
+		keepassPassword = pw
+		fmt.Println()
+	}
+
+	db := gokeepasslib.NewDatabase()
+	db.Credentials = gokeepasslib.NewPasswordCredentials(keepassPassword)
+	if err := gokeepasslib.NewDecoder(file).Decode(db); err != nil {
+		return fmt.Errorf("decoding kdbx: %w", err)
+	}
+	if err := db.UnlockProtectedEntries(); err != nil {
+		return fmt.Errorf("unlocking protected entries: %w", err)
+	}
+	if db.Content == nil || db.Content.Root == nil {
+		return fmt.Errorf("the KeePass File has no content")
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	var recycleBin *gokeepasslib.UUID
+	if db.Content.Meta != nil && db.Content.Meta.RecycleBinEnabled.Bool {
+		recycleBin = &db.Content.Meta.RecycleBinUUID
+	}
+
+	total := 0
+	for _, group := range db.Content.Root.Groups {
+		total += countKeepassEntries(group, recycleBin)
+	}
+
+	pterm.EnableStyling()
+	pterm.DisableColor()
+	progressbar, err := pterm.DefaultProgressbar.WithTitle("Importing Resources").WithTotal(total).Start()
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+
+	stats := &keepassImportStats{}
+
+	// The top level Group is the Database itself (the export names it "root"),
+	// so its Entries land in the target Folder and its Subgroups become Folders.
+	for _, group := range db.Content.Root.Groups {
+		err = importKeepassGroup(ctx, client, group, folderParentID, recycleBin, stats, progressbar)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Imported %v Folders and %v Resources, Skipped %v Entries\n", stats.folders, stats.resources, stats.skipped)
+	return nil
+}
+
+func importKeepassGroup(ctx context.Context, client *api.Client, group gokeepasslib.Group, folderParentID string, recycleBin *gokeepasslib.UUID, stats *keepassImportStats, progressbar *pterm.ProgressbarPrinter) error {
+	for _, entry := range group.Entries {
+		e := parseKeepassEntry(entry)
+
+		_, err := util.CreateImportEntry(ctx, client, folderParentID, e)
+		if err != nil {
+			fmt.Printf("\nSkipping Import of Entry %v Because of: %v\n", e.Name, err)
+			stats.skipped++
+		} else {
+			stats.resources++
+		}
+		progressbar.Increment()
+	}
+
+	for _, subgroup := range group.Groups {
+		if recycleBin != nil && subgroup.UUID.Compare(*recycleBin) {
+			continue
+		}
+
+		folderID, err := util.CreateImportFolder(ctx, client, folderParentID, subgroup.Name)
+		if err != nil {
+			return fmt.Errorf("creating Folder %v: %w", subgroup.Name, err)
+		}
+		stats.folders++
+
+		err = importKeepassGroup(ctx, client, subgroup, folderID, recycleBin, stats, progressbar)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func countKeepassEntries(group gokeepasslib.Group, recycleBin *gokeepasslib.UUID) int {
+	count := len(group.Entries)
+	for _, subgroup := range group.Groups {
+		if recycleBin != nil && subgroup.UUID.Compare(*recycleBin) {
+			continue
+		}
+		count += countKeepassEntries(subgroup, recycleBin)
+	}
+	return count
+}
+
+// parseKeepassEntry is the inverse of getKeepassEntry, it maps the standard
+// KeePass fields back and collects every other field as a custom field.
+func parseKeepassEntry(entry gokeepasslib.Entry) util.ImportEntry {
+	e := util.ImportEntry{
+		Name:        entry.GetTitle(),
+		Username:    entry.GetContent("UserName"),
+		Password:    entry.GetPassword(),
+		Description: entry.GetContent("Notes"),
+	}
+	if uri := entry.GetContent("URL"); uri != "" {
+		e.URIs = []string{uri}
+	}
+
+	if otp := entry.GetContent("otp"); otp != "" {
+		totp, err := util.ParseTOTPURI(otp)
+		if err != nil {
+			fmt.Printf("\nIgnoring TOTP of Entry %v Because of: %v\n", e.Name, err)
+		} else {
+			e.TOTP = totp
+		}
+	}
+
+	for _, value := range entry.Values {
+		if keepassStandardKeys[value.Key] {
+			continue
+		}
+		e.CustomFields = append(e.CustomFields, util.ImportCustomField{Key: value.Key, Value: value.Value.Content})
+	}
+	return e
+}
@@ -0,0 +1,26 @@
+package keepass
+
+import (
+	"testing"
+
+	"github.com/tobischo/gokeepasslib/v3"
+	w "github.com/tobischo/gokeepasslib/v3/wrappers"
+)
+
+func TestParseKeepassEntry_CustomFields(t *testing.T) {
+	entry := gokeepasslib.NewEntry()
+	entry.Values = append(entry.Values,
+		gokeepasslib.ValueData{Key: "Title", Value: gokeepasslib.V{Content: "db"}},
+		gokeepasslib.ValueData{Key: "UserName", Value: gokeepasslib.V{Content: "admin"}},
+		gokeepasslib.ValueData{Key: "Password", Value: gokeepasslib.V{Content: "hunter2", Protected: w.NewBoolWrapper(true)}},
+		gokeepasslib.ValueData{Key: "api_key", Value: gokeepasslib.V{Content: "sk-123", Protected: w.NewBoolWrapper(true)}},
+	)
+
+	e := parseKeepassEntry(entry)
+	if e.Name != "db" || e.Username != "admin" || e.Password != "hunter2" {
+		t.Errorf("standard fields = %+v", e)
+	}
+	if len(e.CustomFields) != 1 || e.CustomFields[0].Key != "api_key" || e.CustomFields[0].Value != "sk-123" {
+		t.Errorf("custom fields = %+v, want only api_key", e.CustomFields)
+	}
+}
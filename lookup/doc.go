@@ -0,0 +1,2 @@
+// Package lookup finds and decrypts single Resources by ID or by Folder path and name.
+package lookup
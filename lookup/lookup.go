@@ -0,0 +1,209 @@
+package lookup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+// Lookup resolves references to Resources and caches every Resource it decrypted.
+// It is safe for concurrent use, concurrent requests for the same Resource
+// only fetch it once.
+type Lookup struct {
+	client *api.Client
+	// fetch gets and decrypts a single Resource by ID
+	fetch func(ctx context.Context, id string) (resource.DecryptedResource, error)
+	// resolve returns the ID of the Resource at a path
+	resolve func(ctx context.Context, path string) (string, error)
+
+	mu        sync.Mutex
+	resources map[string]*cacheEntry
+}
+
+// cacheEntry is a Resource that is being or has been fetched, done is closed once it is
+type cacheEntry struct {
+	done     chan struct{}
+	resource resource.DecryptedResource
+	err      error
+}
+
+// New returns a Lookup using client
+func New(client *api.Client) *Lookup {
+	l := &Lookup{
+		client:    client,
+		resolve:   util.NewResolver(client).Resource,
+		resources: map[string]*cacheEntry{},
+	}
+	l.fetch = l.get
+	return l
+}
+
+// NewAgent returns a Lookup that gets the Resources from the agent, which
+// decrypts them with its logged in client
+func NewAgent() *Lookup {
+	return &Lookup{
+		fetch:     getFromAgent,
+		resolve:   func(ctx context.Context, path string) (string, error) { return util.AgentResolve(path) },
+		resources: map[string]*cacheEntry{},
+	}
+}
+
+// Open returns a Lookup using an unlocked agent if there is one, otherwise
+// one with a newly logged in client. done has to be called once the Lookup is
+// no longer needed, it logs the client out.
+func Open(ctx context.Context) (l *Lookup, done func(), err error) {
+	if util.AgentUnlocked() {
+		return NewAgent(), func() {}, nil
+	}
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return New(client), func() { util.SaveSessionKeysAndLogout(ctx, client) }, nil
+}
+
+// Resource returns the decrypted Resource identified by ref, which is either
+// a Resource ID or the slash separated Folder path and name of the Resource,
+// e.g. "Prod/Databases/postgres".
+func (l *Lookup) Resource(ctx context.Context, ref string) (resource.DecryptedResource, error) {
+	id := ref
+	if uuid.Validate(ref) != nil {
+		var err error
+		id, err = l.ResolvePath(ctx, ref)
+		if err != nil {
+			return resource.DecryptedResource{}, err
+		}
+	}
+
+	l.mu.Lock()
+	entry, ok := l.resources[id]
+	if !ok {
+		entry = &cacheEntry{done: make(chan struct{})}
+		l.resources[id] = entry
+	}
+	l.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return resource.DecryptedResource{}, ctx.Err()
+		}
+	} else {
+		entry.resource, entry.err = l.fetch(ctx, id)
+		close(entry.done)
+	}
+	return entry.resource, entry.err
+}
+
+// Prefetch fetches and decrypts the referenced Resources in parallel using
+// the configured number of workers. Each Resource is only fetched once, even
+// if it is referenced multiple times, by ID and by path. Errors are returned
+// when a Resource is requested with Resource.
+func (l *Lookup) Prefetch(ctx context.Context, refs []string) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, ref := range refs {
+		if !seen[ref] {
+			seen[ref] = true
+			unique = append(unique, ref)
+		}
+	}
+
+	util.ForEachParallel(unique, func(ref string) error {
+		// Skip what is left once the context ended
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		_, err := l.Resource(ctx, ref)
+		return err
+	})
+}
+
+func (l *Lookup) get(ctx context.Context, id string) (resource.DecryptedResource, error) {
+	r, err := l.client.GetResource(ctx, id)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("getting Resource %v: %w", id, err)
+	}
+	rType, err := l.client.GetResourceTypeCached(ctx, r.ResourceTypeID)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("getting Resource Type: %w", err)
+	}
+	secret, err := l.client.GetSecret(ctx, r.ID)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("getting Secret of Resource %v: %w", id, err)
+	}
+	_, metadata, secretFields, err := helper.GetResourceFieldMaps(l.client, *r, *secret, *rType, true)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("decrypting Resource %v: %w", id, err)
+	}
+	return resource.DecryptedResource{Resource: *r, Metadata: metadata, Secret: secretFields}, nil
+}
+
+func getFromAgent(ctx context.Context, id string) (resource.DecryptedResource, error) {
+	data, err := util.AgentResource(id)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("getting Resource %v: %w", id, err)
+	}
+	var r resource.DecryptedResource
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return resource.DecryptedResource{}, fmt.Errorf("parsing Resource %v: %w", id, err)
+	}
+	return r, nil
+}
+
+// ResolvePath returns the ID of the Resource with the given Folder path and
+// name. It is an error if no or more than one Resource matches.
+func (l *Lookup) ResolvePath(ctx context.Context, path string) (string, error) {
+	return l.resolve(ctx, path)
+}
+
+// Field returns a field of a Resource. Besides the metadata and secret fields
+// of the Resource Type (e.g. username, uri or password) this accepts the keys
+// of custom fields.
+func Field(r resource.DecryptedResource, field string) (string, error) {
+	found := false
+	for _, fields := range []map[string]any{r.Metadata, r.Secret} {
+		switch v := fields[field].(type) {
+		case nil:
+		case string:
+			// Some Resource Types keep e.g. the description in the secret
+			// and leave an empty one in the metadata
+			if v != "" {
+				return v, nil
+			}
+			found = true
+		case []any, map[string]any:
+			return "", fmt.Errorf("field %q of Resource %v is not a string", field, r.Resource.ID)
+		default:
+			return fmt.Sprint(v), nil
+		}
+	}
+	if found {
+		return "", nil
+	}
+	for _, f := range util.CustomFields(r.Metadata, r.Secret) {
+		if f.Key == field {
+			return f.Value, nil
+		}
+	}
+	return "", fmt.Errorf("resource %v has no field %q", r.Resource.ID, field)
+}
+
+// TOTP returns the current TOTP code of a Resource and how long it stays valid
+func TOTP(r resource.DecryptedResource, when time.Time) (string, time.Duration, error) {
+	totp, ok := r.Secret["totp"].(map[string]any)
+	if !ok {
+		return "", 0, fmt.Errorf("resource %v has no TOTP", r.Resource.ID)
+	}
+	return util.TOTPCode(totp, when)
+}
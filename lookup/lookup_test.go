@@ -0,0 +1,108 @@
+package lookup
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/spf13/viper"
+)
+
+func TestField(t *testing.T) {
+	r := resource.DecryptedResource{
+		Metadata: map[string]any{
+			"name":        "db",
+			"description": "",
+			"custom_fields": []any{
+				map[string]any{"id": "1", "metadata_key": "port"},
+			},
+		},
+		Secret: map[string]any{
+			"password":    "hunter2",
+			"description": "secret description",
+			"custom_fields": []any{
+				map[string]any{"id": "1", "secret_value": "5432"},
+			},
+		},
+	}
+	for field, want := range map[string]string{
+		"name":        "db",
+		"password":    "hunter2",
+		"description": "secret description",
+		"port":        "5432",
+	} {
+		got, err := Field(r, field)
+		if err != nil || got != want {
+			t.Errorf("Field(%q) = %q, %v, want %q", field, got, err, want)
+		}
+	}
+
+	if _, err := Field(r, "custom_fields"); err == nil {
+		t.Error("expected error for non string field")
+	}
+	if _, err := Field(r, "missing"); err == nil {
+		t.Error("expected error for missing field")
+	}
+}
+
+func TestTOTP_Missing(t *testing.T) {
+	if _, _, err := TOTP(resource.DecryptedResource{}, time.Now()); err == nil {
+		t.Error("expected error for Resource without TOTP")
+	}
+}
+
+func TestPrefetch_Deduplicates(t *testing.T) {
+	viper.Set("workers", 4)
+	defer viper.Set("workers", 0)
+
+	const id = "8e3874ae-4b40-590b-968a-418f704b9d9a"
+	var calls atomic.Int32
+	l := New(nil)
+	l.fetch = func(ctx context.Context, id string) (resource.DecryptedResource, error) {
+		calls.Add(1)
+		time.Sleep(time.Millisecond * 10)
+		if id != "8e3874ae-4b40-590b-968a-418f704b9d9a" {
+			return resource.DecryptedResource{}, errors.New("not found")
+		}
+		return resource.DecryptedResource{Secret: map[string]any{"password": "hunter2"}}, nil
+	}
+
+	const other = "00000000-0000-0000-0000-000000000000"
+	l.Prefetch(context.Background(), []string{id, id, other, id})
+	if calls.Load() != 2 {
+		t.Errorf("fetched %v times, want 2", calls.Load())
+	}
+
+	value, err := l.Value(context.Background(), Reference{Resource: id, Selector: "password"})
+	if err != nil || value != "hunter2" {
+		t.Errorf("Value = %q, %v", value, err)
+	}
+	if _, err := l.Resource(context.Background(), other); err == nil {
+		t.Error("expected cached error")
+	}
+	if calls.Load() != 2 {
+		t.Errorf("fetched %v times after prefetch, want 2", calls.Load())
+	}
+}
+
+func TestPrefetch_StopsWhenCanceled(t *testing.T) {
+	viper.Set("workers", 2)
+	defer viper.Set("workers", 0)
+
+	var calls atomic.Int32
+	l := New(nil)
+	l.fetch = func(ctx context.Context, id string) (resource.DecryptedResource, error) {
+		calls.Add(1)
+		return resource.DecryptedResource{}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.Prefetch(ctx, []string{"8e3874ae-4b40-590b-968a-418f704b9d9a", "00000000-0000-0000-0000-000000000000"})
+	if calls.Load() != 0 {
+		t.Errorf("fetched %v times after cancel, want 0", calls.Load())
+	}
+}
@@ -0,0 +1,105 @@
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/passbolt/go-passbolt-cli/util"
+)
+
+// ReferencePrefix starts a reference to a Resource value
+const ReferencePrefix = "passbolt://"
+
+// Reference selects a value of a Resource, it is written as either
+//
+//	passbolt://<id>[/<selector>]
+//	passbolt://folder/<folder path>/<name>[#<selector>]
+//
+// where the selector defaults to the password and can be totp, a field name,
+// metadata/<field>, secret/<field> or secret/custom_fields/<key>.
+type Reference struct {
+	// Resource is the ID or the Folder path and name of the Resource
+	Resource string
+	Selector string
+}
+
+// IsReference reports whether s is meant to be a Reference
+func IsReference(s string) bool {
+	return strings.HasPrefix(s, ReferencePrefix)
+}
+
+// ParseReference parses a passbolt:// Reference
+func ParseReference(s string) (Reference, error) {
+	if !IsReference(s) {
+		return Reference{}, fmt.Errorf("reference %q does not start with %v", s, ReferencePrefix)
+	}
+	rest := strings.TrimPrefix(s, ReferencePrefix)
+
+	var ref Reference
+	if path, ok := strings.CutPrefix(rest, "folder/"); ok {
+		ref.Resource, ref.Selector, _ = strings.Cut(path, "#")
+		ref.Resource = strings.Trim(ref.Resource, "/")
+		if ref.Resource == "" {
+			return Reference{}, fmt.Errorf("reference %q has no Resource name", s)
+		}
+	} else {
+		// The ID form also accepts # for consistency with the path form
+		sep := strings.IndexAny(rest, "/#")
+		if sep == -1 {
+			ref.Resource = rest
+		} else {
+			ref.Resource, ref.Selector = rest[:sep], rest[sep+1:]
+		}
+		if uuid.Validate(ref.Resource) != nil {
+			return Reference{}, fmt.Errorf("reference %q has no valid Resource ID, use passbolt://folder/<path>/<name> to reference by name", s)
+		}
+	}
+	if ref.Selector == "" {
+		ref.Selector = "password"
+	}
+	return ref, nil
+}
+
+// Value resolves a Reference to the selected value
+func (l *Lookup) Value(ctx context.Context, ref Reference) (string, error) {
+	r, err := l.Resource(ctx, ref.Resource)
+	if err != nil {
+		return "", err
+	}
+
+	side, field, _ := strings.Cut(ref.Selector, "/")
+	switch {
+	case ref.Selector == "totp":
+		code, _, err := TOTP(r, time.Now())
+		return code, err
+	case side == "secret" && strings.HasPrefix(field, "custom_fields/"), side == "custom_fields":
+		key := strings.TrimPrefix(strings.TrimPrefix(ref.Selector, "secret/"), "custom_fields/")
+		for _, f := range util.CustomFields(r.Metadata, r.Secret) {
+			if f.Key == key {
+				return f.Value, nil
+			}
+		}
+		return "", fmt.Errorf("resource %v has no custom field %q", r.Resource.ID, key)
+	case side == "secret" && field != "":
+		return stringField(r.Secret, field, "secret", r.Resource.ID)
+	case side == "metadata" && field != "":
+		return stringField(r.Metadata, field, "metadata", r.Resource.ID)
+	}
+	return Field(r, ref.Selector)
+}
+
+func stringField(fields map[string]any, field, side, id string) (string, error) {
+	switch v := fields[field].(type) {
+	case string:
+		return v, nil
+	case nil:
+		return "", fmt.Errorf("resource %v has no %v field %q", id, side, field)
+	case []any, map[string]any:
+		return "", fmt.Errorf("%v field %q of Resource %v is not a string", side, field, id)
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
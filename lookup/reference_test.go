@@ -0,0 +1,35 @@
+package lookup
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	const id = "8e3874ae-4b40-590b-968a-418f704b9d9a"
+	cases := map[string]Reference{
+		"passbolt://" + id:                                   {Resource: id, Selector: "password"},
+		"passbolt://" + id + "/username":                     {Resource: id, Selector: "username"},
+		"passbolt://" + id + "/totp":                         {Resource: id, Selector: "totp"},
+		"passbolt://" + id + "/secret/custom_fields/api key": {Resource: id, Selector: "secret/custom_fields/api key"},
+		"passbolt://" + id + "#uri":                          {Resource: id, Selector: "uri"},
+		"passbolt://folder/Prod/DB#password":                 {Resource: "Prod/DB", Selector: "password"},
+		"passbolt://folder/Prod/DB":                          {Resource: "Prod/DB", Selector: "password"},
+		"passbolt://folder/DB#secret/custom_fields/port":     {Resource: "DB", Selector: "secret/custom_fields/port"},
+	}
+	for s, want := range cases {
+		got, err := ParseReference(s)
+		if err != nil || got != want {
+			t.Errorf("ParseReference(%q) = %+v, %v, want %+v", s, got, err, want)
+		}
+	}
+
+	for _, s := range []string{
+		"https://example.com",
+		"passbolt://not-a-uuid/username",
+		"passbolt://folder/#password",
+	} {
+		if _, err := ParseReference(s); err == nil {
+			t.Errorf("ParseReference(%q): expected error", s)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package render implements rendering text templates that reference Passbolt secrets.
+package render
@@ -0,0 +1,38 @@
+package render
+
+import (
+	"context"
+	"text/template"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/lookup"
+)
+
+// templateFuncs returns the functions available in templates. Resources are
+// referenced by ID or by Folder path and name.
+func templateFuncs(ctx context.Context, l *lookup.Lookup) template.FuncMap {
+	return template.FuncMap{
+		"secret": func(ref string) (string, error) {
+			r, err := l.Resource(ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			return lookup.Field(r, "password")
+		},
+		"field": func(ref, field string) (string, error) {
+			r, err := l.Resource(ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			return lookup.Field(r, field)
+		},
+		"totp": func(ref string) (string, error) {
+			r, err := l.Resource(ctx, ref)
+			if err != nil {
+				return "", err
+			}
+			code, _, err := lookup.TOTP(r, time.Now())
+			return code, err
+		},
+	}
+}
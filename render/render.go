@@ -0,0 +1,129 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"text/template"
+
+	"github.com/passbolt/go-passbolt-cli/lookup"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/spf13/cobra"
+)
+
+// RenderCmd Renders a Template with Secrets
+var RenderCmd = &cobra.Command{
+	Use:   "render",
+	Short: "Renders a Template with Secrets from Passbolt",
+	Long: `Renders a Go text/template, replacing references to Resources with their Secrets.
+Resources are referenced by ID or by Folder path and name, the following Functions exist:
+
+  {{ secret "Prod/Databases/postgres" }}                       the Password
+  {{ field "<PASSBOLT_RESOURCE_ID_HERE>" "username" }}         any Field, including Custom Fields by Key
+  {{ totp "Prod/github" }}                                     the current TOTP Code
+
+For example:
+  passbolt render -i app.conf.tmpl -o app.conf
+
+The Output File is only written if the whole Template rendered and is created with 0600 Permissions.`,
+	Args: cobra.NoArgs,
+	RunE: Render,
+}
+
+func init() {
+	RenderCmd.Flags().StringP("input", "i", "-", "Template File, - reads from stdin")
+	RenderCmd.Flags().StringP("output", "o", "-", "Output File, - writes to stdout")
+	RenderCmd.Flags().String("mode", "0600", "Permissions of the Output File")
+}
+
+func Render(cmd *cobra.Command, args []string) error {
+	input, err := cmd.Flags().GetString("input")
+	if err != nil {
+		return err
+	}
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	modeFlag, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return err
+	}
+	mode, err := strconv.ParseUint(modeFlag, 8, 32)
+	if err != nil || mode > 0777 {
+		return fmt.Errorf("invalid mode %q, must be octal permissions like 0600", modeFlag)
+	}
+
+	var text []byte
+	if input == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return fmt.Errorf("reading Template: %w", err)
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	l, done, err := lookup.Open(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	cmd.SilenceUsage = true
+
+	tmpl, err := template.New(filepath.Base(input)).
+		Option("missingkey=error").
+		Funcs(templateFuncs(ctx, l)).
+		Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("parsing Template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, nil)
+	if err != nil {
+		return fmt.Errorf("rendering Template: %w", err)
+	}
+
+	if output == "-" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return writeFile(output, buf.Bytes(), os.FileMode(mode))
+}
+
+// writeFile replaces the file atomically, so readers never see partial
+// content and the secrets are never written with broader permissions
+func writeFile(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("creating Output File: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing Output File: %w", err)
+	}
+	err = tmp.Chmod(mode)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("setting Output File Permissions: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("writing Output File: %w", err)
+	}
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("writing Output File: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	err := os.WriteFile(path, []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = writeFile(path, []byte("password=hunter2\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "password=hunter2\n" {
+		t.Errorf("content = %q", data)
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestTemplateFuncs_Parse(t *testing.T) {
+	_, err := template.New("t").Funcs(templateFuncs(nil, nil)).Parse(`{{ secret "a" }} {{ field "a" "username" }} {{ totp "a" }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = template.New("t").Funcs(templateFuncs(nil, nil)).Parse(`{{ password "a" }}`)
+	if err == nil || !strings.Contains(err.Error(), "password") {
+		t.Errorf("unknown function err = %v", err)
+	}
+}
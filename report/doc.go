@@ -0,0 +1,2 @@
+// Package report implements reports over the Resources of a Passbolt Server.
+package report
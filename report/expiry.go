@@ -0,0 +1,218 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+)
+
+// ReportExpiryCmd Reports expired and expiring Resources
+var ReportExpiryCmd = &cobra.Command{
+	Use:   "expiry",
+	Short: "Reports expired and expiring Resources",
+	Long: `Reports the Resources that are expired or expire within --within, grouped by Folder and by Owner.
+Use --json for alerting, it lists every Resource once together with its Folder and Owners.`,
+	Args: cobra.NoArgs,
+	RunE: ReportExpiry,
+}
+
+func init() {
+	ReportExpiryCmd.Flags().String("within", "30d", "Also report Resources that expire within this duration")
+}
+
+// expiryReport is the JSON output of the expiry report
+type expiryReport struct {
+	Generated time.Time           `json:"generated"`
+	Deadline  time.Time           `json:"deadline"`
+	Expired   int                 `json:"expired"`
+	Expiring  int                 `json:"expiring"`
+	Resources []expiryEntry       `json:"resources"`
+	ByFolder  map[string][]string `json:"by_folder"`
+	ByOwner   map[string][]string `json:"by_owner"`
+}
+
+// expiryEntry is a Resource in the expiry report
+type expiryEntry struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Folder  string    `json:"folder"`
+	Owners  []string  `json:"owners"`
+	Expiry  time.Time `json:"expiry"`
+	Expired bool      `json:"expired"`
+}
+
+func ReportExpiry(cmd *cobra.Command, args []string) error {
+	within, err := cmd.Flags().GetString("within")
+	if err != nil {
+		return err
+	}
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	duration, err := resource.ParseDuration(within)
+	if err != nil {
+		return fmt.Errorf("invalid --within: %w", err)
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	folders, err := client.GetFolders(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing Folder: %w", err)
+	}
+	users, err := client.GetUsers(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing User: %w", err)
+	}
+	groups, err := client.GetGroups(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing Group: %w", err)
+	}
+	resources, err := resource.GetDecryptedResources(ctx, client, &api.GetResourcesOptions{
+		ContainPermissions: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	names := map[string]string{}
+	for _, u := range users {
+		names[u.ID] = u.Username
+	}
+	for _, g := range groups {
+		names[g.ID] = "group:" + g.Name
+	}
+
+	now := time.Now()
+	report := buildExpiryReport(resources, util.FolderPaths(folders), names, now, now.Add(duration))
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	printExpiryReport(report)
+	return nil
+}
+
+// buildExpiryReport collects the Resources expiring before deadline, names
+// maps User and Group IDs to the names used for Owners
+func buildExpiryReport(resources []resource.DecryptedResource, folderPaths map[string][]string, names map[string]string, now, deadline time.Time) expiryReport {
+	report := expiryReport{
+		Generated: now.UTC(),
+		Deadline:  deadline.UTC(),
+		Resources: []expiryEntry{},
+		ByFolder:  map[string][]string{},
+		ByOwner:   map[string][]string{},
+	}
+	for _, r := range resources {
+		if r.Resource.Expired == nil || !r.Resource.Expired.Before(deadline) {
+			continue
+		}
+		entry := expiryEntry{
+			ID:      r.Resource.ID,
+			Name:    helper.GetStringField(r.Metadata, "name"),
+			Folder:  "/" + strings.Join(folderPaths[r.Resource.FolderParentID], "/"),
+			Owners:  []string{},
+			Expiry:  r.Resource.Expired.UTC(),
+			Expired: !r.Resource.Expired.After(now),
+		}
+		for _, p := range r.Resource.Permissions {
+			if p.Type != 15 {
+				continue
+			}
+			owner, ok := names[p.AROForeignKey]
+			if !ok {
+				owner = strings.ToLower(p.ARO) + ":" + p.AROForeignKey
+			}
+			entry.Owners = append(entry.Owners, owner)
+		}
+		sort.Strings(entry.Owners)
+
+		if entry.Expired {
+			report.Expired++
+		} else {
+			report.Expiring++
+		}
+		report.Resources = append(report.Resources, entry)
+	}
+
+	sort.SliceStable(report.Resources, func(i, j int) bool {
+		return report.Resources[i].Expiry.Before(report.Resources[j].Expiry)
+	})
+	for _, e := range report.Resources {
+		report.ByFolder[e.Folder] = append(report.ByFolder[e.Folder], e.ID)
+		for _, owner := range e.Owners {
+			report.ByOwner[owner] = append(report.ByOwner[owner], e.ID)
+		}
+	}
+	return report
+}
+
+func printExpiryReport(report expiryReport) {
+	fmt.Printf("%v expired, %v expiring before %v\n", report.Expired, report.Expiring, report.Deadline.Format(time.RFC3339))
+	if len(report.Resources) == 0 {
+		return
+	}
+	byID := map[string]expiryEntry{}
+	for _, e := range report.Resources {
+		byID[e.ID] = e
+	}
+
+	for _, grouping := range []struct {
+		title  string
+		column string
+		groups map[string][]string
+	}{
+		{"By Folder", "Folder", report.ByFolder},
+		{"By Owner", "Owner", report.ByOwner},
+	} {
+		fmt.Printf("\n%v:\n", grouping.title)
+		keys := make([]string, 0, len(grouping.groups))
+		for key := range grouping.groups {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		data := pterm.TableData{{grouping.column, "ID", "Name", "Expiry", "Status"}}
+		for _, key := range keys {
+			for _, id := range grouping.groups[key] {
+				e := byID[id]
+				status := "expiring"
+				if e.Expired {
+					status = "expired"
+				}
+				data = append(data, []string{
+					shellescape.StripUnsafe(key),
+					e.ID,
+					shellescape.StripUnsafe(e.Name),
+					e.Expiry.Format(time.RFC3339),
+					status,
+				})
+			}
+		}
+		pterm.DefaultTable.WithHasHeader().WithData(data).Render()
+	}
+}
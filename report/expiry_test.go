@@ -0,0 +1,66 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/resource"
+	"github.com/passbolt/go-passbolt/api"
+)
+
+func TestBuildExpiryReport(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *api.Time {
+		return &api.Time{Time: now.Add(d)}
+	}
+	owner := func(aro, id string) api.Permission {
+		return api.Permission{ARO: aro, AROForeignKey: id, Type: 15}
+	}
+	resources := []resource.DecryptedResource{
+		{
+			Resource: api.Resource{ID: "r1", FolderParentID: "f1", Expired: at(10 * 24 * time.Hour),
+				Permissions: []api.Permission{owner("User", "u1"), {ARO: "User", AROForeignKey: "u2", Type: 1}}},
+			Metadata: map[string]any{"name": "db"},
+		},
+		{
+			Resource: api.Resource{ID: "r2", Expired: at(-time.Hour),
+				Permissions: []api.Permission{owner("Group", "g1"), owner("User", "u1")}},
+			Metadata: map[string]any{"name": "api"},
+		},
+		{
+			Resource: api.Resource{ID: "r3", Expired: at(60 * 24 * time.Hour)},
+			Metadata: map[string]any{"name": "later"},
+		},
+		{
+			Resource: api.Resource{ID: "r4"},
+			Metadata: map[string]any{"name": "never"},
+		},
+	}
+	folderPaths := map[string][]string{"f1": {"Prod", "DB"}}
+	names := map[string]string{"u1": "ada@example.com", "g1": "group:Ops"}
+
+	report := buildExpiryReport(resources, folderPaths, names, now, now.Add(30*24*time.Hour))
+
+	if report.Expired != 1 || report.Expiring != 1 {
+		t.Errorf("got %v expired and %v expiring, want 1 and 1", report.Expired, report.Expiring)
+	}
+	ids := []string{}
+	for _, e := range report.Resources {
+		ids = append(ids, e.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"r2", "r1"}) {
+		t.Errorf("resources = %v, want [r2 r1] sorted by expiry", ids)
+	}
+	if got := report.Resources[1].Folder; got != "/Prod/DB" {
+		t.Errorf("folder = %q, want /Prod/DB", got)
+	}
+	wantFolders := map[string][]string{"/": {"r2"}, "/Prod/DB": {"r1"}}
+	if !reflect.DeepEqual(report.ByFolder, wantFolders) {
+		t.Errorf("ByFolder = %v, want %v", report.ByFolder, wantFolders)
+	}
+	wantOwners := map[string][]string{"ada@example.com": {"r2", "r1"}, "group:Ops": {"r2"}}
+	if !reflect.DeepEqual(report.ByOwner, wantOwners) {
+		t.Errorf("ByOwner = %v, want %v", report.ByOwner, wantOwners)
+	}
+}
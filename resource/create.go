@@ -1,10 +1,13 @@
 package resource
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/passbolt/go-passbolt-cli/generate"
 	"github.com/passbolt/go-passbolt-cli/util"
 	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
@@ -24,9 +27,11 @@ func init() {
 	ResourceCreateCmd.Flags().StringP("username", "u", "", "Resource Username")
 	ResourceCreateCmd.Flags().String("uri", "", "Resource URI")
 	ResourceCreateCmd.Flags().StringP("password", "p", "", "Resource Password")
+	ResourceCreateCmd.Flags().Bool("generate", false, "Generate the Password according to the Password Policy of the Server, the --generate- Flags override its Settings")
+	generate.AddGeneratorFlags(ResourceCreateCmd.Flags(), "generate-")
 	ResourceCreateCmd.Flags().StringP("description", "d", "", "Resource Description")
-	ResourceCreateCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to create the Resource")
-	ResourceCreateCmd.Flags().String("expiry", "", "Expiry as RFC3339 (e.g. 2025-12-31T23:59:59Z) or Go duration (e.g. 48h, 30m)")
+	ResourceCreateCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to create the Resource, by id or path")
+	ResourceCreateCmd.Flags().String("expiry", "", "Expiry as RFC3339 (e.g. 2025-12-31T23:59:59Z) or duration (e.g. 90d, 48h)")
 	ResourceCreateCmd.Flags().String("type", "", "Resource type slug (e.g. v5-default, password-and-description, v5-custom-fields)")
 	ResourceCreateCmd.Flags().StringArray("field", []string{}, "Metadata field as key=value (repeatable; JSON values like [\"a\"] are parsed automatically)")
 	ResourceCreateCmd.Flags().StringArray("secret-field", []string{}, "Secret field as key=value (repeatable; JSON values are parsed automatically)")
@@ -49,6 +54,17 @@ func ResourceCreate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	generate, err := cmd.Flags().GetBool("generate")
+	if err != nil {
+		return err
+	}
+	if generate && password != "" {
+		return fmt.Errorf("--password can't be used with --generate")
+	}
+	err = checkGenerateFlags(cmd, generate)
+	if err != nil {
+		return err
+	}
 	description, err := cmd.Flags().GetString("description")
 	if err != nil {
 		return err
@@ -90,6 +106,31 @@ func ResourceCreate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	folderParentID, err = util.NewResolver(client).Folder(ctx, folderParentID)
+	if err != nil {
+		return err
+	}
+
+	var generated string
+	if generate {
+		password, generated, err = generatePassword(ctx, client, cmd)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !useGeneric {
+		if name == "" {
+			return fmt.Errorf("required flag \"name\" not set")
+		}
+		if password == "" {
+			return fmt.Errorf("required flag \"password\" not set")
+		}
+		// helper.CreateResource picks the Resource Type with the settings Login
+		// loads, a resumed session did not log in so it is picked here instead
+		useGeneric = util.SessionResumed(client)
+	}
+
 	var id string
 
 	if useGeneric {
@@ -133,21 +174,23 @@ func ResourceCreate(cmd *cobra.Command, args []string) error {
 		}
 
 		if resourceType == "" {
-			if client.MetadataTypeSettings().DefaultResourceType == api.PassboltAPIVersionTypeV5 {
+			if util.DefaultResourceTypeIsV5(client) {
 				resourceType = "v5-default"
 			} else {
 				resourceType = "password-and-description"
 			}
 		}
 
+		if util.DryRun() {
+			return dryRunResourceCreate(ctx, client, resourceType, folderParentID, metadataFields, secretFieldsMap, expiry)
+		}
+
 		id, err = helper.CreateResourceGeneric(ctx, client, resourceType, folderParentID, metadataFields, secretFieldsMap)
 	} else {
 		// Legacy path: use standard CreateResource
-		if name == "" {
-			return fmt.Errorf("required flag \"name\" not set")
-		}
-		if password == "" {
-			return fmt.Errorf("required flag \"password\" not set")
+		if util.DryRun() {
+			metadataFields := map[string]any{"name": name, "username": username, "uri": uri, "description": description}
+			return dryRunResourceCreate(ctx, client, "", folderParentID, metadataFields, map[string]any{"password": password}, expiry)
 		}
 		id, err = helper.CreateResource(ctx, client, folderParentID, name, username, uri, password, description)
 	}
@@ -162,6 +205,10 @@ func ResourceCreate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if generated != "" {
+		printGenerated(generated, id)
+	}
+
 	if jsonOutput {
 		jsonID, err := json.MarshalIndent(map[string]string{"id": id}, "", "  ")
 		if err != nil {
@@ -174,6 +221,53 @@ func ResourceCreate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// dryRunResourceCreate prints the Resource that would be created, an empty
+// slug stands for the default Resource Type. Field values are not printed.
+func dryRunResourceCreate(ctx context.Context, client *api.Client, slug, folderParentID string, metadata, secret map[string]any, expiry string) error {
+	folder, err := util.DescribeFolder(ctx, client, folderParentID)
+	if err != nil {
+		return err
+	}
+	if slug == "" {
+		slug = "the default type"
+	}
+	util.PrintDryRun("POST", "/resources.json", "create Resource %q of %v in %v, metadata fields %v, secret fields %v",
+		metadata["name"], slug, folder, util.FieldNames(metadata), util.FieldNames(secret))
+
+	if expiry != "" {
+		return SetResourceExpiry(ctx, client, "<new>", expiry)
+	}
+	return nil
+}
+
+// checkGenerateFlags rejects the --generate- flags without --generate
+func checkGenerateFlags(cmd *cobra.Command, enabled bool) error {
+	if flag := generate.ChangedGeneratorFlag(cmd, "generate-"); flag != "" && !enabled {
+		return fmt.Errorf("--%v requires --generate", flag)
+	}
+	return nil
+}
+
+// generatePassword generates a Password or Passphrase with the settings of the
+// Password Policy and the --generate- flags, it also returns which of both
+func generatePassword(ctx context.Context, client *api.Client, cmd *cobra.Command) (string, string, error) {
+	policy, err := util.GetPasswordPolicy(ctx, client)
+	if err != nil {
+		return "", "", err
+	}
+	return generate.Generate(cmd, "generate-", policy, true)
+}
+
+// printGenerated tells where to find what --generate generated, without
+// printing the secret itself
+func printGenerated(kind, id string) {
+	name := "Password"
+	if kind == "passphrase" {
+		name = "Passphrase"
+	}
+	fmt.Fprintf(os.Stderr, "Generated a %v, show it with: passbolt get resource --id %v\n", name, id)
+}
+
 // parseKeyValue parses a "key=value" string. If the value looks like JSON
 // (starts with [ or {), it is decoded into the appropriate Go type so that
 // it is serialized correctly when marshaled back to JSON.
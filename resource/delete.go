@@ -1,9 +1,11 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/spf13/cobra"
 )
 
@@ -11,19 +13,35 @@ import (
 var ResourceDeleteCmd = &cobra.Command{
 	Use:   "resource",
 	Short: "Deletes a Passbolt Resource",
-	Long:  `Deletes a Passbolt Resource`,
+	Long:  `Deletes a Passbolt Resource, or all Resources matching --filter after a Confirmation`,
 	RunE:  ResourceDelete,
 }
 
+func init() {
+	ResourceDeleteCmd.Flags().String("filter", "", "CEL expression selecting the Resources to delete instead of --id, see \"list resource --filter\"")
+	ResourceDeleteCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
+}
+
 func ResourceDelete(cmd *cobra.Command, args []string) error {
 	resourceID, err := cmd.Flags().GetString("id")
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
-	if resourceID == "" {
+	if resourceID == "" && filter == "" {
 		return fmt.Errorf("no ID to Delete Provided")
 	}
+	if resourceID != "" && filter != "" {
+		return fmt.Errorf("--id can't be used with --filter")
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -35,7 +53,35 @@ func ResourceDelete(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = client.DeleteResource(ctx, resourceID)
+	resourceID, err = util.NewResolver(client).Resource(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectResourceTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Deleted", "Resources", yes, func(id string) error {
+			return DeleteResource(ctx, client, id)
+		})
+	}
+	return DeleteResource(ctx, client, resourceID)
+}
+
+// DeleteResource deletes a Resource, with --dry-run it only prints the call
+func DeleteResource(ctx context.Context, client *api.Client, resourceID string) error {
+	if util.DryRun() {
+		resource, err := util.DescribeResource(ctx, client, resourceID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("DELETE", fmt.Sprintf("/resources/%s.json", resourceID), "delete %v", resource)
+		return nil
+	}
+
+	err := client.DeleteResource(ctx, resourceID)
 	if err != nil {
 		return fmt.Errorf("deleting Resource: %w", err)
 	}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/passbolt/go-passbolt-cli/util"
 	"github.com/passbolt/go-passbolt/api"
 )
 
@@ -16,6 +18,19 @@ func SetResourceExpiry(ctx context.Context, client *api.Client, id string, expir
 		return nil
 	}
 
+	if util.DryRun() {
+		if strings.ToLower(expiryInput) == "none" {
+			util.PrintDryRun("PUT", fmt.Sprintf("/resources/%s.json", id), "clear expiry of Resource %v", id)
+			return nil
+		}
+		isoExpiry, err := ParseExpiry(expiryInput)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/resources/%s.json", id), "set expiry of Resource %v to %v", id, isoExpiry)
+		return nil
+	}
+
 	// Safety: ensure the resource id is a UUID to avoid unsafe URL construction
 	if !isUUID(id) {
 		return fmt.Errorf("invalid resource id: %q", id)
@@ -73,13 +88,46 @@ func ParseExpiry(input string) (string, error) {
 		return t.UTC().Format(time.RFC3339), nil
 	}
 	// Fallback to human duration(s)
-	d, err := time.ParseDuration(input)
+	d, err := ParseDuration(input)
 	if err != nil {
 		return "", fmt.Errorf("invalid expiry value %q: %w", input, err)
 	}
 	return time.Now().UTC().Add(d).Format(time.RFC3339), nil
 }
 
+// ParseDuration parses a Go duration that may also use the units d for days
+// and w for weeks, like "30d" or "1w12h".
+func ParseDuration(input string) (time.Duration, error) {
+	if input == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	rest := input
+	var total time.Duration
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(rest, unit.suffix)
+		if i == -1 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		total += time.Duration(n) * unit.length
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", input)
+		}
+		total += d
+	}
+	return total, nil
+}
+
 func tryParseAbsoluteTime(s string) (time.Time, error) {
 	// Try RFC3339 variants only (avoid nonstandard timestamp formats)
 	layouts := []string{
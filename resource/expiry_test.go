@@ -29,22 +29,27 @@ func TestParseExpiry_Absolute(t *testing.T) {
 }
 
 func TestParseExpiry_Duration(t *testing.T) {
-	// Duration inputs are parsed via time.ParseDuration, so only h/m/s units
-	// are accepted — d and w are not supported despite the flag's help text
-	// suggesting otherwise. This locks in the actual behavior; if the CLI
-	// grows custom day/week parsing this test will fail and prompt updating.
-	before := time.Now().UTC()
-	got, err := ParseExpiry("48h")
-	if err != nil {
-		t.Fatalf("ParseExpiry(48h) errored: %v", err)
-	}
-	after := time.Now().UTC()
-	gotT, err := time.Parse(time.RFC3339, got)
-	if err != nil {
-		t.Fatalf("returned value not RFC3339: %q", got)
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"48h", 48 * time.Hour},
+		{"7d", 7 * 24 * time.Hour},
+		{"1w2d3h", 9*24*time.Hour + 3*time.Hour},
 	}
-	if d := gotT.Sub(before); d < 47*time.Hour || d > 49*time.Hour {
-		t.Errorf("ParseExpiry(48h) yielded %v, expected ~48h from now (%v..%v)", gotT, before, after)
+	for _, tc := range cases {
+		before := time.Now().UTC()
+		got, err := ParseExpiry(tc.in)
+		if err != nil {
+			t.Fatalf("ParseExpiry(%v) errored: %v", tc.in, err)
+		}
+		gotT, err := time.Parse(time.RFC3339, got)
+		if err != nil {
+			t.Fatalf("returned value not RFC3339: %q", got)
+		}
+		if d := gotT.Sub(before); d < tc.want-time.Hour || d > tc.want+time.Hour {
+			t.Errorf("ParseExpiry(%v) yielded %v, expected ~%v from now", tc.in, gotT, tc.want)
+		}
 	}
 }
 
@@ -53,8 +58,7 @@ func TestParseExpiry_Errors(t *testing.T) {
 		name string
 		in   string
 	}{
-		{"7d not supported by time.ParseDuration", "7d"},
-		{"1w2d3h not supported", "1w2d3h"},
+		{"units out of order", "2d1w"},
 		{"completely invalid", "tomorrow"},
 		{"non-rfc3339 timestamp", "2030/01/01"},
 	}
@@ -77,6 +81,31 @@ func TestParseExpiry_Empty(t *testing.T) {
 	}
 }
 
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"30d", 30 * 24 * time.Hour},
+		{"1w2d3h", 9*24*time.Hour + 3*time.Hour},
+		{"12h30m", 12*time.Hour + 30*time.Minute},
+		{"0d", 0},
+	}
+	for _, tc := range cases {
+		got, err := ParseDuration(tc.in)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) errored: %v", tc.in, err)
+		} else if got != tc.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+	for _, in := range []string{"", "d", "-1d", "2d1w", "soon"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) expected error, got nil", in)
+		}
+	}
+}
+
 func TestIsUUID(t *testing.T) {
 	cases := []struct {
 		in   string
@@ -3,9 +3,11 @@ package resource
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 )
 
 // CelEnvOptions defines the CEL environment for resource filtering
@@ -19,10 +21,46 @@ var CelEnvOptions = []cel.EnvOption{
 	cel.Variable("Description", cel.StringType),
 	cel.Variable("CreatedTimestamp", cel.TimestampType),
 	cel.Variable("ModifiedTimestamp", cel.TimestampType),
+	// Expiry is the zero timestamp for Resources without Expiry, see HasExpiry
+	cel.Variable("Expiry", cel.TimestampType),
+	cel.Variable("HasExpiry", cel.BoolType),
 	cel.Variable("Metadata", cel.MapType(cel.StringType, cel.DynType)),
 	cel.Variable("Secret", cel.MapType(cel.StringType, cel.DynType)),
 }
 
+// selectResources returns the Resources matching a CEL expression, secrets
+// are only decrypted if the expression references them
+func selectResources(ctx context.Context, client *api.Client, celCmd string) ([]decryptedResource, error) {
+	needSecrets, err := util.CELExpressionReferencesFields(celCmd, []string{"Password", "Description", "Secret"}, CelEnvOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("parsing filter: %w", err)
+	}
+	resources, err := client.GetResources(ctx, &api.GetResourcesOptions{
+		ContainSecret: needSecrets,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing Resource: %w", err)
+	}
+	decrypted, err := decryptResourcesParallel(ctx, client, resources, needSecrets)
+	if err != nil {
+		return nil, err
+	}
+	return filterDecryptedResources(decrypted, celCmd, ctx)
+}
+
+// selectResourceTargets returns the Resources matching a CEL expression for a bulk operation
+func selectResourceTargets(ctx context.Context, client *api.Client, celCmd string) ([]util.BulkTarget, error) {
+	resources, err := selectResources(ctx, client, celCmd)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]util.BulkTarget, len(resources))
+	for i, r := range resources {
+		targets[i] = util.BulkTarget{ID: r.resource.ID, Description: r.name}
+	}
+	return targets, nil
+}
+
 // filterDecryptedResources filters already-decrypted resources by evaluating a CEL expression.
 func filterDecryptedResources(resources []decryptedResource, celCmd string, ctx context.Context) ([]decryptedResource, error) {
 	if celCmd == "" {
@@ -46,6 +84,11 @@ func filterDecryptedResources(resources []decryptedResource, celCmd string, ctx
 			secret = map[string]any{}
 		}
 
+		var expiry time.Time
+		if d.resource.Expired != nil {
+			expiry = d.resource.Expired.Time
+		}
+
 		val, _, err := (*program).ContextEval(ctx, map[string]any{
 			"ID":                d.resource.ID,
 			"FolderParentID":    d.resource.FolderParentID,
@@ -56,6 +99,8 @@ func filterDecryptedResources(resources []decryptedResource, celCmd string, ctx
 			"Description":       d.description,
 			"CreatedTimestamp":  d.resource.Created.Time,
 			"ModifiedTimestamp": d.resource.Modified.Time,
+			"Expiry":            expiry,
+			"HasExpiry":         d.resource.Expired != nil,
 			"Metadata":          metadata,
 			"Secret":            secret,
 		})
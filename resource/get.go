@@ -32,12 +32,12 @@ var ResourcePermissionCmd = &cobra.Command{
 }
 
 func init() {
-	ResourceGetCmd.Flags().String("id", "", "id of Resource to Get")
+	ResourceGetCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Get")
 
 	ResourceGetCmd.MarkFlagRequired("id")
 
 	ResourceGetCmd.AddCommand(ResourcePermissionCmd)
-	ResourcePermissionCmd.Flags().String("id", "", "id of Resource to Get")
+	ResourcePermissionCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Get")
 	ResourcePermissionCmd.Flags().StringArrayP("column", "c", []string{"ID", "Aco", "AcoForeignKey", "Aro", "AroForeignKey", "Type"}, "Columns to return, possible Columns:\nID, Aco, AcoForeignKey, Aro, AroForeignKey, Type, CreatedTimestamp, ModifiedTimestamp")
 
 	ResourcePermissionCmd.MarkFlagRequired("id")
@@ -63,6 +63,11 @@ func ResourceGet(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	resource, err := client.GetResource(ctx, id)
 	if err != nil {
 		return fmt.Errorf("getting resource: %w", err)
@@ -156,6 +161,11 @@ func ResourcePermission(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	resource, err = util.NewResolver(client).Resource(ctx, resource)
+	if err != nil {
+		return err
+	}
+
 	permissions, err := client.GetResourcePermissions(ctx, resource)
 	if err != nil {
 		return fmt.Errorf("listing Permission: %w", err)
@@ -12,6 +12,7 @@ type ResourceJSONOutput struct {
 	Description       *string        `json:"description,omitempty"`
 	CreatedTimestamp  *time.Time     `json:"created_timestamp,omitempty"`
 	ModifiedTimestamp *time.Time     `json:"modified_timestamp,omitempty"`
+	Expiry            *time.Time     `json:"expiry,omitempty"`
 	Metadata          map[string]any `json:"metadata,omitempty"`
 	Secret            map[string]any `json:"secret,omitempty"`
 }
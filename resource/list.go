@@ -50,7 +50,8 @@ func init() {
 	flags.Bool("own", false, "Resources that are owned by me")
 	flags.StringP("group", "g", "", "Resources that are shared with group")
 	flags.StringArrayP("folder", "f", []string{}, "Resources that are in folder")
-	flags.StringArrayP("column", "c", defaultTableColumns, "Columns to return (default list only for table format; JSON format includes all fields by default).\nPossible Columns: ID, FolderParentID, Name, Username, URI, Password, Description, CreatedTimestamp, ModifiedTimestamp")
+	flags.String("expiring-within", "", "Resources that are expired or expire within this duration, e.g. 30d")
+	flags.StringArrayP("column", "c", defaultTableColumns, "Columns to return (default list only for table format; JSON format includes all fields by default).\nPossible Columns: ID, FolderParentID, Name, Username, URI, Password, Description, CreatedTimestamp, ModifiedTimestamp, Expiry")
 }
 
 type resourceListConfig struct {
@@ -58,6 +59,7 @@ type resourceListConfig struct {
 	own            bool
 	group          string
 	folderParents  []string
+	expiringWithin time.Duration
 	columns        []string
 	columnsChanged bool
 	jsonOutput     bool
@@ -116,6 +118,10 @@ func ResourceList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if config.expiringWithin >= 0 {
+		decrypted = filterExpiringResources(decrypted, time.Now().Add(config.expiringWithin))
+	}
+
 	// Apply CEL filter on already-decrypted data
 	if config.celFilter != "" {
 		decrypted, err = filterDecryptedResources(decrypted, config.celFilter, ctx)
@@ -131,6 +137,50 @@ func ResourceList(cmd *cobra.Command, args []string) error {
 	return printTableResources(decrypted, config.columns)
 }
 
+// filterExpiringResources keeps the Resources that expire before deadline
+func filterExpiringResources(resources []decryptedResource, deadline time.Time) []decryptedResource {
+	filtered := []decryptedResource{}
+	for _, d := range resources {
+		if d.resource.Expired != nil && d.resource.Expired.Before(deadline) {
+			filtered = append(filtered, d)
+		}
+	}
+	return filtered
+}
+
+// DecryptedResource is a Resource together with its decrypted metadata and
+// secret fields
+type DecryptedResource struct {
+	Resource api.Resource
+	Metadata map[string]any
+	Secret   map[string]any
+}
+
+// GetDecryptedResources lists and decrypts Resources the same way "list
+// resource" does, secrets are only decrypted if opts.ContainSecret is set.
+// Resources of unsupported Resource Types are skipped with a warning.
+func GetDecryptedResources(ctx context.Context, client *api.Client, opts *api.GetResourcesOptions) ([]DecryptedResource, error) {
+	resources, err := client.GetResources(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing Resource: %w", err)
+	}
+
+	decrypted, err := decryptResourcesParallel(ctx, client, resources, opts != nil && opts.ContainSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]DecryptedResource, len(decrypted))
+	for i, d := range decrypted {
+		result[i] = DecryptedResource{
+			Resource: d.resource,
+			Metadata: d.metadataFields,
+			Secret:   d.secretFields,
+		}
+	}
+	return result, nil
+}
+
 func decryptResourcesParallel(ctx context.Context, client *api.Client, resources []api.Resource, needSecrets bool) ([]decryptedResource, error) {
 	// Use parallel decryption with worker pool
 	numWorkers := int(viper.GetUint("workers"))
@@ -305,6 +355,9 @@ func printJSONResources(
 			CreatedTimestamp:  &d.resource.Created.Time,
 			ModifiedTimestamp: &d.resource.Modified.Time,
 		}
+		if d.resource.Expired != nil {
+			output.Expiry = &d.resource.Expired.Time
+		}
 		if len(d.metadataFields) > 0 {
 			output.Metadata = d.metadataFields
 		}
@@ -377,6 +430,10 @@ func printTableResources(
 				entry[i] = d.resource.Created.Format(time.RFC3339)
 			case "modifiedtimestamp":
 				entry[i] = d.resource.Modified.Format(time.RFC3339)
+			case "expiry":
+				if d.resource.Expired != nil {
+					entry[i] = d.resource.Expired.Format(time.RFC3339)
+				}
 			default:
 				return fmt.Errorf("unknown Column: %v", columns[i])
 			}
@@ -405,6 +462,17 @@ func parseResourceListFlags(cmd *cobra.Command) (*resourceListConfig, error) {
 	if err != nil {
 		return nil, err
 	}
+	expiringWithin := time.Duration(-1)
+	if cmd.Flags().Changed("expiring-within") {
+		within, err := cmd.Flags().GetString("expiring-within")
+		if err != nil {
+			return nil, err
+		}
+		expiringWithin, err = ParseDuration(within)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --expiring-within: %w", err)
+		}
+	}
 	columns, err := cmd.Flags().GetStringArray("column")
 	if err != nil {
 		return nil, err
@@ -426,6 +494,7 @@ func parseResourceListFlags(cmd *cobra.Command) (*resourceListConfig, error) {
 		own:            own,
 		group:          group,
 		folderParents:  folderParents,
+		expiringWithin: expiringWithin,
 		columns:        columns,
 		columnsChanged: cmd.Flags().Changed("column"),
 		jsonOutput:     jsonOutput,
@@ -1,9 +1,11 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,15 +14,18 @@ import (
 var ResourceMoveCmd = &cobra.Command{
 	Use:   "resource",
 	Short: "Moves a Passbolt Resource into a Folder",
-	Long:  `Moves a Passbolt Resource into a Folder`,
+	Long:  `Moves a Passbolt Resource, or all Resources matching --filter after a Confirmation, into a Folder`,
 	RunE:  ResourceMove,
 }
 
 func init() {
-	ResourceMoveCmd.Flags().String("id", "", "id of Resource to Move")
-	ResourceMoveCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to Move the Resource")
+	ResourceMoveCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Move")
+	ResourceMoveCmd.Flags().StringP("folderParentID", "f", "", "Folder in which to Move the Resource, by id or path")
+	ResourceMoveCmd.Flags().String("filter", "", "CEL expression selecting the Resources to move instead of --id, see \"list resource --filter\"")
+	ResourceMoveCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	ResourceMoveCmd.MarkFlagRequired("id")
+	ResourceMoveCmd.MarkFlagsOneRequired("id", "filter")
+	ResourceMoveCmd.MarkFlagsMutuallyExclusive("id", "filter")
 	ResourceMoveCmd.MarkFlagRequired("folderParentID")
 }
 
@@ -33,6 +38,14 @@ func ResourceMove(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -44,7 +57,45 @@ func ResourceMove(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.MoveResource(
+	resolver := util.NewResolver(client)
+	id, err = resolver.Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+	folderParentID, err = resolver.Folder(ctx, folderParentID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectResourceTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Moved", "Resources", yes, func(id string) error {
+			return MoveResource(ctx, client, id, folderParentID)
+		})
+	}
+	return MoveResource(ctx, client, id, folderParentID)
+}
+
+// MoveResource moves a Resource into a Folder, "" is the root. With --dry-run
+// it only prints the call
+func MoveResource(ctx context.Context, client *api.Client, id, folderParentID string) error {
+	if util.DryRun() {
+		resource, err := util.DescribeResource(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		folder, err := util.DescribeFolder(ctx, client, folderParentID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("POST", fmt.Sprintf("/move/resource/%s.json", id), "move %v into %v", resource, folder)
+		return nil
+	}
+
+	err := helper.MoveResource(
 		ctx,
 		client,
 		id,
@@ -0,0 +1,186 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/generate"
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/spf13/cobra"
+)
+
+// ResourceRotateCmd Rotates the Password of a Passbolt Resource
+var ResourceRotateCmd = &cobra.Command{
+	Use:   "resource [-- hook [args...]]",
+	Short: "Rotates the Password of a Passbolt Resource",
+	Long: `Generates a new Password according to the Password Policy, stores it in the Resource and sets a fresh Expiry.
+The --generate- Flags override Settings of the Password Policy like for "generate password".
+
+If a Hook Command is given after --, it is run before the Resource is changed, e.g. to set the new Password
+in a Database. The Hook gets the old and the new Password as two Lines on stdin and the Resource ID in
+the PASSBOLT_RESOURCE_ID Environment Variable. The new Password is only stored if the Hook succeeds. If it
+can't be stored after that, it is written to a temporary File only you can read and the Path is printed:
+
+	passbolt rotate resource --id <id> -- sh -c 'read -r old; read -r new; psql -c "ALTER USER app PASSWORD '\''$new'\''"'`,
+	Args: cobra.ArbitraryArgs,
+	RunE: ResourceRotate,
+}
+
+// rotateUpdateRetries is how often storing a password the hook applied is retried
+const rotateUpdateRetries = 2
+
+func init() {
+	ResourceRotateCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Rotate")
+	ResourceRotateCmd.Flags().String("expiry", "90d", "New Expiry as RFC3339 (e.g. 2025-12-31T23:59:59Z), duration (e.g. 90d, 12h), or 'none' to clear")
+	generate.AddGeneratorFlags(ResourceRotateCmd.Flags(), "generate-")
+
+	ResourceRotateCmd.MarkFlagRequired("id")
+}
+
+func ResourceRotate(cmd *cobra.Command, args []string) error {
+	id, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return err
+	}
+	expiry, err := cmd.Flags().GetString("expiry")
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(expiry) != "none" {
+		if _, err := ParseExpiry(expiry); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	// ctx is replaced after running the hook
+	defer func() { util.SaveSessionKeysAndLogout(ctx, client) }()
+	cmd.SilenceUsage = true
+
+	id, err = util.NewResolver(client).Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	oldPassword, err := getResourcePassword(ctx, client, id)
+	if err != nil {
+		return err
+	}
+	newPassword, _, err := generatePassword(ctx, client, cmd)
+	if err != nil {
+		return err
+	}
+
+	if util.DryRun() {
+		resource, err := util.DescribeResource(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		if len(args) > 0 {
+			fmt.Printf("[dry-run] would run hook %q\n", strings.Join(args, " "))
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/resources/%s.json", id), "rotate password of %v", resource)
+		return SetResourceExpiry(ctx, client, id, expiry)
+	}
+
+	if len(args) > 0 {
+		err = runRotateHook(cmd.Context(), args, id, oldPassword, newPassword)
+		if err != nil {
+			return fmt.Errorf("hook failed, Resource %v was not changed: %w", id, err)
+		}
+	}
+
+	// The hook may have taken longer than the timeout
+	ctx, cancel = util.GetContext()
+	defer cancel()
+
+	err = helper.UpdateResourceGeneric(ctx, client, id, nil, map[string]any{"password": newPassword})
+	if err != nil && len(args) > 0 {
+		// The hook already applied the new password, don't lose it
+		err = retryRotateUpdate(ctx, client, id, newPassword, err)
+	}
+	if err != nil {
+		return fmt.Errorf("updating resource: %w", err)
+	}
+	err = SetResourceExpiry(ctx, client, id, expiry)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Rotated Resource %v\n", id)
+	return nil
+}
+
+// getResourcePassword returns the decrypted password of a Resource
+func getResourcePassword(ctx context.Context, client *api.Client, id string) (string, error) {
+	resource, err := client.GetResource(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("getting resource: %w", err)
+	}
+	rType, err := client.GetResourceTypeCached(ctx, resource.ResourceTypeID)
+	if err != nil {
+		return "", fmt.Errorf("getting resource type: %w", err)
+	}
+	secret, err := client.GetSecret(ctx, resource.ID)
+	if err != nil {
+		return "", fmt.Errorf("getting secret: %w", err)
+	}
+	_, _, secretFields, err := helper.GetResourceFieldMaps(client, *resource, *secret, *rType, true)
+	if err != nil {
+		return "", fmt.Errorf("decrypting resource: %w", err)
+	}
+	return helper.GetStringField(secretFields, "password"), nil
+}
+
+// retryRotateUpdate retries storing a password the hook already applied. If
+// that fails too the password is saved to a file only the user can read, so it
+// is neither lost nor printed into logs.
+func retryRotateUpdate(ctx context.Context, client *api.Client, id, newPassword string, err error) error {
+	for attempt := 0; attempt < rotateUpdateRetries && err != nil; attempt++ {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-time.After(time.Second * 2):
+			err = helper.UpdateResourceGeneric(ctx, client, id, nil, map[string]any{"password": newPassword})
+		}
+	}
+	if err == nil {
+		return nil
+	}
+
+	file, createErr := os.CreateTemp("", "passbolt-rotate-"+id+"-*")
+	if createErr != nil {
+		return fmt.Errorf("%w, the new Password applied by the Hook could not be saved either: %v", err, createErr)
+	}
+	defer file.Close()
+	_, writeErr := file.WriteString(newPassword + "\n")
+	if writeErr != nil {
+		os.Remove(file.Name())
+		return fmt.Errorf("%w, the new Password applied by the Hook could not be saved either: %v", err, writeErr)
+	}
+	fmt.Fprintf(os.Stderr, "The Hook succeeded but the Resource could not be updated, the new Password was saved to %v\n", file.Name())
+	return err
+}
+
+// runRotateHook runs the hook with the old and new password on stdin, it is
+// killed when ctx ends
+func runRotateHook(ctx context.Context, args []string, id, oldPassword, newPassword string) error {
+	hook := exec.CommandContext(ctx, args[0], args[1:]...)
+	hook.Stdin = strings.NewReader(oldPassword + "\n" + newPassword + "\n")
+	hook.Stdout = os.Stdout
+	hook.Stderr = os.Stderr
+	hook.Env = append(os.Environ(), "PASSBOLT_RESOURCE_ID="+id)
+	return hook.Run()
+}
@@ -1,9 +1,11 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,17 +14,20 @@ import (
 var ResourceShareCmd = &cobra.Command{
 	Use:   "resource",
 	Short: "Shares a Passbolt Resource",
-	Long:  `Shares a Passbolt Resource`,
+	Long:  `Shares a Passbolt Resource, or all Resources matching --filter after a Confirmation`,
 	RunE:  ResourceShare,
 }
 
 func init() {
-	ResourceShareCmd.Flags().String("id", "", "id of Resource to Share")
+	ResourceShareCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Share")
 	ResourceShareCmd.Flags().IntP("type", "t", 1, "Permission Type (1 Read Only, 7 Can Update, 15 Owner)")
-	ResourceShareCmd.Flags().StringArrayP("user", "u", []string{}, "User id's to share with")
-	ResourceShareCmd.Flags().StringArrayP("group", "g", []string{}, "Group id's to share with")
+	ResourceShareCmd.Flags().StringArrayP("user", "u", []string{}, "Users (id or Username) to share with")
+	ResourceShareCmd.Flags().StringArrayP("group", "g", []string{}, "Groups (id or name) to share with")
+	ResourceShareCmd.Flags().String("filter", "", "CEL expression selecting the Resources to share instead of --id, see \"list resource --filter\"")
+	ResourceShareCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	ResourceShareCmd.MarkFlagRequired("id")
+	ResourceShareCmd.MarkFlagsOneRequired("id", "filter")
+	ResourceShareCmd.MarkFlagsMutuallyExclusive("id", "filter")
 	ResourceShareCmd.MarkFlagRequired("type")
 }
 
@@ -43,6 +48,14 @@ func ResourceShare(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -54,7 +67,47 @@ func ResourceShare(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.ShareResourceWithUsersAndGroups(
+	resolver := util.NewResolver(client)
+	id, err = resolver.Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+	users, err = resolver.Users(ctx, users)
+	if err != nil {
+		return err
+	}
+	groups, err = resolver.Groups(ctx, groups)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectResourceTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Shared", "Resources", yes, func(id string) error {
+			return shareResource(ctx, client, id, users, groups, pType)
+		})
+	}
+	return shareResource(ctx, client, id, users, groups, pType)
+}
+
+func shareResource(ctx context.Context, client *api.Client, id string, users, groups []string, pType int) error {
+	if util.DryRun() {
+		resource, err := util.DescribeResource(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		share, err := util.DescribeShare(ctx, client, users, groups, pType)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/share/resource/%s.json", id), "share %v with %v", resource, share)
+		return nil
+	}
+
+	err := helper.ShareResourceWithUsersAndGroups(
 		ctx,
 		client,
 		id,
@@ -0,0 +1,190 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// ResourceTOTPCmd Gets the current TOTP Code of a Passbolt Resource
+var ResourceTOTPCmd = &cobra.Command{
+	Use:   "totp",
+	Short: "Gets the current TOTP Code of a Passbolt Resource",
+	Long: `Gets the current TOTP Code of a Passbolt Resource and how long it stays valid.
+Use --quiet to only print the Code, e.g. for scripts, or --watch to keep printing new Codes.`,
+	RunE: ResourceTOTP,
+}
+
+type totpJSONOutput struct {
+	Code             string `json:"code"`
+	RemainingSeconds int    `json:"remaining_seconds"`
+}
+
+func init() {
+	ResourceTOTPCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to get the TOTP Code of")
+	ResourceTOTPCmd.Flags().BoolP("quiet", "q", false, "Only print the Code")
+	ResourceTOTPCmd.Flags().BoolP("watch", "w", false, "Keep printing the current Code until interrupted")
+
+	ResourceTOTPCmd.MarkFlagRequired("id")
+}
+
+func ResourceTOTP(cmd *cobra.Command, args []string) error {
+	id, err := cmd.Flags().GetString("id")
+	if err != nil {
+		return err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return err
+	}
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return err
+	}
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+
+	totp, err := getResourceTOTP(id)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	// Fail before watching if the TOTP is unusable
+	code, remaining, err := util.TOTPCode(totp, time.Now())
+	if err != nil {
+		return fmt.Errorf("generating TOTP Code: %w", err)
+	}
+
+	if watch {
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		return watchTOTP(ctx, totp, quiet || jsonOutput)
+	}
+
+	switch {
+	case jsonOutput:
+		out, err := json.MarshalIndent(totpJSONOutput{
+			Code:             code,
+			RemainingSeconds: int(remaining.Seconds()),
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case quiet:
+		fmt.Println(code)
+	default:
+		fmt.Printf("Code: %v\n", code)
+		fmt.Printf("Remaining: %v\n", remaining)
+	}
+	return nil
+}
+
+// getResourceTOTP returns the totp secret field of a Resource, the session
+// is closed right away as the codes are generated locally
+func getResourceTOTP(id string) (map[string]any, error) {
+	if util.AgentUnlocked() {
+		return getAgentTOTP(id)
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+
+	id, err = util.NewResolver(client).Resource(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := client.GetResource(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource: %w", err)
+	}
+	rType, err := client.GetResourceTypeCached(ctx, resource.ResourceTypeID)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource type: %w", err)
+	}
+	secret, err := client.GetSecret(ctx, resource.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting secret: %w", err)
+	}
+	_, _, secretFields, err := helper.GetResourceFieldMaps(client, *resource, *secret, *rType, true)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting resource: %w", err)
+	}
+
+	totp, ok := secretFields["totp"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("resource %v of type %v has no TOTP", id, rType.Slug)
+	}
+	return totp, nil
+}
+
+// watchTOTP prints the code every time it changes. In a terminal the line
+// is updated every second to count down the remaining time.
+func watchTOTP(ctx context.Context, totp map[string]any, codeOnly bool) error {
+	countdown := !codeOnly && term.IsTerminal(int(os.Stdout.Fd()))
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		code, remaining, err := util.TOTPCode(totp, time.Now())
+		if err != nil {
+			return fmt.Errorf("generating TOTP Code: %w", err)
+		}
+		switch {
+		case countdown:
+			fmt.Printf("\r%v  %3v remaining", code, remaining)
+		case code != last && codeOnly:
+			fmt.Println(code)
+		case code != last:
+			fmt.Printf("%v  valid for %v\n", code, remaining)
+		}
+		last = code
+
+		select {
+		case <-ctx.Done():
+			if countdown {
+				fmt.Println()
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// getAgentTOTP returns the totp secret field of a Resource decrypted by the
+// agent, for --watch which runs locally
+func getAgentTOTP(id string) (map[string]any, error) {
+	data, err := util.AgentResource(id)
+	if err != nil {
+		return nil, fmt.Errorf("getting resource: %w", err)
+	}
+	var r DecryptedResource
+	err = json.Unmarshal(data, &r)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resource: %w", err)
+	}
+	totp, ok := r.Secret["totp"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("resource %v has no TOTP", r.Resource.ID)
+	}
+	return totp, nil
+}
@@ -1,9 +1,13 @@
 package resource
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
+	"github.com/passbolt/go-passbolt-cli/generate"
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,21 +16,28 @@ import (
 var ResourceUpdateCmd = &cobra.Command{
 	Use:   "resource",
 	Short: "Updates a Passbolt Resource",
-	Long:  `Updates a Passbolt Resource`,
+	Long:  `Updates a Passbolt Resource, or the Expiry of all Resources matching --filter after a Confirmation`,
 	RunE:  ResourceUpdate,
 }
 
 func init() {
-	ResourceUpdateCmd.Flags().String("id", "", "id of Resource to Update")
+	ResourceUpdateCmd.Flags().String("id", "", "id or path (/Folder/Name) of Resource to Update")
 	ResourceUpdateCmd.Flags().StringP("name", "n", "", "Resource Name")
 	ResourceUpdateCmd.Flags().StringP("username", "u", "", "Resource Username")
 	ResourceUpdateCmd.Flags().String("uri", "", "Resource URI")
 	ResourceUpdateCmd.Flags().StringP("password", "p", "", "Resource Password")
+	ResourceUpdateCmd.Flags().Bool("generate", false, "Generate the Password according to the Password Policy of the Server, the --generate- Flags override its Settings")
+	generate.AddGeneratorFlags(ResourceUpdateCmd.Flags(), "generate-")
 	ResourceUpdateCmd.Flags().StringP("description", "d", "", "Resource Description")
 	ResourceUpdateCmd.Flags().String("expiry", "", "Expiry as RFC3339 (e.g. 2025-12-31T23:59:59Z), duration (e.g. 7d, 12h), or 'none' to clear")
 	ResourceUpdateCmd.Flags().StringArray("field", []string{}, "Metadata field as key=value (repeatable; JSON values like [\"a\"] are parsed automatically)")
 	ResourceUpdateCmd.Flags().StringArray("secret-field", []string{}, "Secret field as key=value (repeatable; JSON values are parsed automatically)")
-	ResourceUpdateCmd.MarkFlagRequired("id")
+	ResourceUpdateCmd.Flags().String("filter", "", "CEL expression selecting the Resources to update instead of --id, only --expiry can be updated this way.\n"+
+		"The same variables as in \"list resource --filter\" are available, e.g. --filter '!HasExpiry && FolderParentID == \"<id>\"'")
+	ResourceUpdateCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
+
+	ResourceUpdateCmd.MarkFlagsOneRequired("id", "filter")
+	ResourceUpdateCmd.MarkFlagsMutuallyExclusive("id", "filter")
 }
 
 func ResourceUpdate(cmd *cobra.Command, args []string) error {
@@ -50,6 +61,17 @@ func ResourceUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	generate, err := cmd.Flags().GetBool("generate")
+	if err != nil {
+		return err
+	}
+	if generate && password != "" {
+		return fmt.Errorf("--password can't be used with --generate")
+	}
+	err = checkGenerateFlags(cmd, generate)
+	if err != nil {
+		return err
+	}
 	description, err := cmd.Flags().GetString("description")
 	if err != nil {
 		return err
@@ -66,6 +88,25 @@ func ResourceUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+	if filter != "" {
+		for _, flag := range []string{"name", "username", "uri", "password", "generate", "description", "field", "secret-field"} {
+			if cmd.Flags().Changed(flag) {
+				return fmt.Errorf("--%v can't be used with --filter, only --expiry can be updated for multiple Resources", flag)
+			}
+		}
+		if expiry == "" {
+			return fmt.Errorf("--filter requires --expiry")
+		}
+		return updateResourcesExpiry(cmd, filter, expiry, yes)
+	}
 
 	useGeneric := len(fields) > 0 || len(secretFields) > 0
 
@@ -79,6 +120,19 @@ func ResourceUpdate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).Resource(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var generated string
+	if generate {
+		password, generated, err = generatePassword(ctx, client, cmd)
+		if err != nil {
+			return err
+		}
+	}
+
 	if useGeneric {
 		// Generic path: use UpdateResourceGeneric with field maps
 		metadataUpdates := map[string]any{}
@@ -115,8 +169,26 @@ func ResourceUpdate(cmd *cobra.Command, args []string) error {
 			secretUpdates[k] = v
 		}
 
+		if util.DryRun() {
+			return dryRunResourceUpdate(ctx, client, id, metadataUpdates, secretUpdates, expiry)
+		}
+
 		err = helper.UpdateResourceGeneric(ctx, client, id, metadataUpdates, secretUpdates)
 	} else {
+		if util.DryRun() {
+			metadataUpdates := map[string]any{}
+			secretUpdates := map[string]any{}
+			for k, v := range map[string]string{"name": name, "username": username, "uri": uri, "description": description} {
+				if v != "" {
+					metadataUpdates[k] = v
+				}
+			}
+			if password != "" {
+				secretUpdates["password"] = password
+			}
+			return dryRunResourceUpdate(ctx, client, id, metadataUpdates, secretUpdates, expiry)
+		}
+
 		err = helper.UpdateResource(ctx, client, id, name, username, uri, password, description)
 	}
 
@@ -129,5 +201,49 @@ func ResourceUpdate(cmd *cobra.Command, args []string) error {
 			return err
 		}
 	}
+	if generated != "" {
+		printGenerated(generated, id)
+	}
 	return nil
 }
+
+// updateResourcesExpiry sets the expiry of all Resources matching filter after a confirmation
+func updateResourcesExpiry(cmd *cobra.Command, filter, expiry string, yes bool) error {
+	if strings.ToLower(expiry) != "none" {
+		if _, err := ParseExpiry(expiry); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := util.GetContext()
+	defer cancel()
+
+	client, err := util.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer util.SaveSessionKeysAndLogout(ctx, client)
+	cmd.SilenceUsage = true
+
+	targets, err := selectResourceTargets(ctx, client, filter)
+	if err != nil {
+		return err
+	}
+	return util.RunBulk(targets, "Updated the Expiry of", "Resources", yes, func(id string) error {
+		return SetResourceExpiry(ctx, client, id, expiry)
+	})
+}
+
+// dryRunResourceUpdate prints which fields of the Resource would be updated.
+// Field values are not printed.
+func dryRunResourceUpdate(ctx context.Context, client *api.Client, id string, metadata, secret map[string]any, expiry string) error {
+	resource, err := util.DescribeResource(ctx, client, id)
+	if err != nil {
+		return err
+	}
+	if len(metadata) != 0 || len(secret) != 0 {
+		util.PrintDryRun("PUT", fmt.Sprintf("/resources/%s.json", id), "update %v, metadata fields %v, secret fields %v",
+			resource, util.FieldNames(metadata), util.FieldNames(secret))
+	}
+	return SetResourceExpiry(ctx, client, id, expiry)
+}
@@ -60,6 +60,11 @@ func UserCreate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	if util.DryRun() {
+		util.PrintDryRun("POST", "/users.json", "create User %q (%v %v) with Role %v", username, firstname, lastname, role)
+		return nil
+	}
+
 	id, err := helper.CreateUser(
 		ctx,
 		client,
@@ -1,9 +1,11 @@
 package user
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,19 +14,35 @@ import (
 var UserDeleteCmd = &cobra.Command{
 	Use:   "user",
 	Short: "Deletes a Passbolt User",
-	Long:  `Deletes a Passbolt User`,
+	Long:  `Deletes a Passbolt User, or all Users matching --filter after a Confirmation`,
 	RunE:  UserDelete,
 }
 
+func init() {
+	UserDeleteCmd.Flags().String("filter", "", "CEL expression selecting the Users to delete instead of --id, see \"list user --filter\"")
+	UserDeleteCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
+}
+
 func UserDelete(cmd *cobra.Command, args []string) error {
 	resourceID, err := cmd.Flags().GetString("id")
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
-	if resourceID == "" {
+	if resourceID == "" && filter == "" {
 		return fmt.Errorf("no ID to Delete Provided")
 	}
+	if resourceID != "" && filter != "" {
+		return fmt.Errorf("--id can't be used with --filter")
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -36,7 +54,34 @@ func UserDelete(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.DeleteUser(ctx, client, resourceID)
+	resourceID, err = util.NewResolver(client).User(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectUserTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Deleted", "Users", yes, func(id string) error {
+			return deleteUser(ctx, client, id)
+		})
+	}
+	return deleteUser(ctx, client, resourceID)
+}
+
+func deleteUser(ctx context.Context, client *api.Client, resourceID string) error {
+	if util.DryRun() {
+		user, err := util.DescribeUser(ctx, client, resourceID)
+		if err != nil {
+			return err
+		}
+		util.PrintDryRun("DELETE", fmt.Sprintf("/users/%s.json", resourceID), "delete %v", user)
+		return nil
+	}
+
+	err := helper.DeleteUser(ctx, client, resourceID)
 	if err != nil {
 		return fmt.Errorf("deleting User: %w", err)
 	}
@@ -58,3 +58,20 @@ func filterUsers(users *[]api.User, celCmd string, ctx context.Context) ([]api.U
 
 	return filteredUsers, nil
 }
+
+// selectUserTargets returns the Users matching a CEL expression for a bulk operation
+func selectUserTargets(ctx context.Context, client *api.Client, celCmd string) ([]util.BulkTarget, error) {
+	users, err := client.GetUsers(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing User: %w", err)
+	}
+	users, err = filterUsers(&users, celCmd, ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]util.BulkTarget, len(users))
+	for i, u := range users {
+		targets[i] = util.BulkTarget{ID: u.ID, Description: u.Username}
+	}
+	return targets, nil
+}
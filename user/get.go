@@ -19,7 +19,7 @@ var UserGetCmd = &cobra.Command{
 }
 
 func init() {
-	UserGetCmd.Flags().String("id", "", "id of User to Get")
+	UserGetCmd.Flags().String("id", "", "id or Username of User to Get")
 
 	UserGetCmd.MarkFlagRequired("id")
 }
@@ -44,6 +44,11 @@ func UserGet(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
+	id, err = util.NewResolver(client).User(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	role, username, firstname, lastname, err := helper.GetUser(
 		ctx,
 		client,
@@ -1,9 +1,11 @@
 package user
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/passbolt/go-passbolt-cli/util"
+	"github.com/passbolt/go-passbolt/api"
 	"github.com/passbolt/go-passbolt/helper"
 	"github.com/spf13/cobra"
 )
@@ -12,17 +14,20 @@ import (
 var UserUpdateCmd = &cobra.Command{
 	Use:   "user",
 	Short: "Updates a Passbolt User",
-	Long:  `Updates a Passbolt User`,
+	Long:  `Updates a Passbolt User, or all Users matching --filter after a Confirmation`,
 	RunE:  UserUpdate,
 }
 
 func init() {
-	UserUpdateCmd.Flags().String("id", "", "id of User to Update")
+	UserUpdateCmd.Flags().String("id", "", "id or Username of User to Update")
 	UserUpdateCmd.Flags().StringP("firstname", "f", "", "User FirstName")
 	UserUpdateCmd.Flags().StringP("lastname", "l", "", "User LastName")
 	UserUpdateCmd.Flags().StringP("role", "r", "", "User Role")
+	UserUpdateCmd.Flags().String("filter", "", "CEL expression selecting the Users to update instead of --id, see \"list user --filter\"")
+	UserUpdateCmd.Flags().BoolP("yes", "y", false, "Don't ask for Confirmation when using --filter")
 
-	UserUpdateCmd.MarkFlagRequired("id")
+	UserUpdateCmd.MarkFlagsOneRequired("id", "filter")
+	UserUpdateCmd.MarkFlagsMutuallyExclusive("id", "filter")
 }
 
 func UserUpdate(cmd *cobra.Command, args []string) error {
@@ -42,6 +47,14 @@ func UserUpdate(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	filter, err := cmd.Flags().GetString("filter")
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
 
 	ctx, cancel := util.GetContext()
 	defer cancel()
@@ -53,7 +66,40 @@ func UserUpdate(cmd *cobra.Command, args []string) error {
 	defer util.SaveSessionKeysAndLogout(ctx, client)
 	cmd.SilenceUsage = true
 
-	err = helper.UpdateUser(
+	id, err = util.NewResolver(client).User(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if filter != "" {
+		targets, err := selectUserTargets(ctx, client, filter)
+		if err != nil {
+			return err
+		}
+		return util.RunBulk(targets, "Updated", "Users", yes, func(id string) error {
+			return updateUser(ctx, client, id, firstname, lastname, role)
+		})
+	}
+	return updateUser(ctx, client, id, firstname, lastname, role)
+}
+
+func updateUser(ctx context.Context, client *api.Client, id, firstname, lastname, role string) error {
+	if util.DryRun() {
+		user, err := util.DescribeUser(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		changes := map[string]any{}
+		for k, v := range map[string]string{"firstname": firstname, "lastname": lastname, "role": role} {
+			if v != "" {
+				changes[k] = v
+			}
+		}
+		util.PrintDryRun("PUT", fmt.Sprintf("/users/%s.json", id), "update %v, fields %v", user, util.FieldNames(changes))
+		return nil
+	}
+
+	err := helper.UpdateUser(
 		ctx,
 		client,
 		id,
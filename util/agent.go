@@ -0,0 +1,285 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/viper"
+)
+
+// AgentRequest is sent by the CLI to the agent. The first request of a
+// connection names the Command, later ones answer Prompts of the agent.
+type AgentRequest struct {
+	// Command is one of run, resolve, resource, unlock, lock or status
+	Command string `json:"command,omitempty"`
+	// KeyID and Server identify the account a request is for
+	KeyID      string `json:"key_id,omitempty"`
+	Server     string `json:"server,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+
+	// Args, Dir and Env describe the invocation to run
+	Args []string `json:"args,omitempty"`
+	Dir  string   `json:"dir,omitempty"`
+	Env  []string `json:"env,omitempty"`
+
+	// Ref is the ID or path of the Resource to resolve or get
+	Ref string `json:"ref,omitempty"`
+
+	// Input answers a Prompt, Error is set if it could not be answered
+	Input string `json:"input,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// AgentResponse is what the agent sends back. Output and Prompts are sent
+// while a request is handled, the last response has neither.
+type AgentResponse struct {
+	Locked      bool          `json:"locked"`
+	IdleTimeout time.Duration `json:"idle_timeout,omitempty"`
+	Error       string        `json:"error,omitempty"`
+
+	Stdout []byte `json:"stdout,omitempty"`
+	Stderr []byte `json:"stderr,omitempty"`
+	// Prompt asks the CLI for input, Secret means it must not be echoed
+	Prompt string `json:"prompt,omitempty"`
+	Secret bool   `json:"secret,omitempty"`
+
+	ExitCode int             `json:"exit_code,omitempty"`
+	ID       string          `json:"id,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+}
+
+// final returns if this is the last response to a request
+func (r AgentResponse) final() bool {
+	return r.Stdout == nil && r.Stderr == nil && r.Prompt == ""
+}
+
+// AgentRun connects an invocation the agent runs for a CLI to the agents
+// client and to the CLI that asked for it
+type AgentRun struct {
+	// Context ends when the CLI disconnects or the agent is locked
+	Context context.Context
+	// Client returns the logged in client of the agent
+	Client func(ctx context.Context) (*api.Client, error)
+	// Prompt asks the CLI for input
+	Prompt func(prompt string, secret bool) (string, error)
+}
+
+// agentRun is set while the agent handles a request of a CLI
+var agentRun *AgentRun
+
+// SetAgentRun is used by the agent while it handles a request, nil ends it
+func SetAgentRun(run *AgentRun) {
+	agentRun = run
+}
+
+// InAgent returns if this is an invocation run by the agent
+func InAgent() bool {
+	return agentRun != nil
+}
+
+// AgentSocketPath returns the path of the agents unix socket
+func AgentSocketPath() (string, error) {
+	if path := viper.GetString("agentSocket"); path != "" {
+		return path, nil
+	}
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		confDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("getting Config Directory: %w", err)
+		}
+		dir = confDir
+	}
+	return filepath.Join(dir, "go-passbolt-cli", "agent.sock"), nil
+}
+
+// AgentKeyID identifies a private key towards the agent without revealing it
+func AgentKeyID(privateKey string) string {
+	sum := sha256.Sum256([]byte(privateKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// agentAccount returns the request fields identifying the configured account
+func agentAccount(req AgentRequest) AgentRequest {
+	req.KeyID = AgentKeyID(viper.GetString("userPrivateKey"))
+	req.Server = viper.GetString("serverAddress")
+	return req
+}
+
+func dialAgent() (net.Conn, error) {
+	path, err := AgentSocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to Agent: %w", err)
+	}
+	return conn, nil
+}
+
+// CallAgent sends a request to the agent and returns its final response.
+// Prompts of the agent are answered on the terminal.
+func CallAgent(req AgentRequest) (AgentResponse, error) {
+	conn, err := dialAgent()
+	if err != nil {
+		return AgentResponse{}, err
+	}
+	defer conn.Close()
+	// Other requests may wait for a running invocation or for input
+	if req.Command == "status" {
+		conn.SetDeadline(time.Now().Add(time.Second * 10))
+	}
+
+	res, err := exchangeAgent(conn, req)
+	if err != nil {
+		return res, err
+	}
+	if res.Error != "" {
+		return res, fmt.Errorf("agent: %v", res.Error)
+	}
+	return res, nil
+}
+
+// exchangeAgent sends req and handles the responses until the final one
+func exchangeAgent(conn net.Conn, req AgentRequest) (AgentResponse, error) {
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+	err := enc.Encode(req)
+	if err != nil {
+		return AgentResponse{}, fmt.Errorf("sending Agent Request: %w", err)
+	}
+	for {
+		var res AgentResponse
+		err = dec.Decode(&res)
+		if err != nil {
+			return AgentResponse{}, fmt.Errorf("reading Agent Response: %w", err)
+		}
+		if res.final() {
+			return res, nil
+		}
+		os.Stdout.Write(res.Stdout)
+		os.Stderr.Write(res.Stderr)
+		if res.Prompt == "" {
+			continue
+		}
+
+		var answer AgentRequest
+		if res.Secret {
+			answer.Input, err = ReadPassword(res.Prompt)
+		} else {
+			answer.Input, err = ask(res.Prompt)
+		}
+		if err != nil {
+			answer.Error = err.Error()
+		}
+		err = enc.Encode(answer)
+		if err != nil {
+			return AgentResponse{}, fmt.Errorf("sending Agent Input: %w", err)
+		}
+	}
+}
+
+// agentAvailable returns if an agent socket exists and the Passphrase was not given otherwise
+func agentAvailable() bool {
+	if InAgent() || viper.GetString("userPassword") != "" {
+		return false
+	}
+	path, err := AgentSocketPath()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// AgentUnlocked returns if a running agent is unlocked for the configured account
+func AgentUnlocked() bool {
+	if !agentAvailable() {
+		return false
+	}
+	res, err := CallAgent(agentAccount(AgentRequest{Command: "status"}))
+	if err != nil {
+		if viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, "Getting Agent Status:", err)
+		}
+		return false
+	}
+	return !res.Locked
+}
+
+// RunInAgent runs the invocation with args in a running and unlocked agent, so
+// it uses the agents logged in client. It returns false if there is no agent
+// to run it, otherwise the exit code of the invocation.
+func RunInAgent(args []string) (bool, int) {
+	if !agentAvailable() {
+		return false, 0
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return false, 0
+	}
+	conn, err := dialAgent()
+	if err != nil {
+		if viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return false, 0
+	}
+	defer conn.Close()
+
+	res, err := exchangeAgent(conn, agentAccount(AgentRequest{
+		Command: "run",
+		Args:    args,
+		Dir:     dir,
+		Env:     os.Environ(),
+	}))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return true, 1
+	}
+	if res.Locked {
+		if viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, "Agent is locked", res.Error)
+		}
+		return false, 0
+	}
+	if res.Error != "" {
+		fmt.Fprintln(os.Stderr, "Error: agent:", res.Error)
+		return true, 1
+	}
+	return true, res.ExitCode
+}
+
+// AgentResolve resolves a Resource path with the agent
+func AgentResolve(ref string) (string, error) {
+	res, err := CallAgent(agentAccount(AgentRequest{Command: "resolve", Ref: ref}))
+	if err != nil {
+		return "", err
+	}
+	if res.Locked {
+		return "", errors.New("agent is locked")
+	}
+	return res.ID, nil
+}
+
+// AgentResource gets a decrypted Resource from the agent, it is returned as JSON
+func AgentResource(id string) (json.RawMessage, error) {
+	res, err := CallAgent(agentAccount(AgentRequest{Command: "resource", Ref: id}))
+	if err != nil {
+		return nil, err
+	}
+	if res.Locked {
+		return nil, errors.New("agent is locked")
+	}
+	return res.Resource, nil
+}
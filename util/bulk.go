@@ -0,0 +1,87 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"golang.org/x/term"
+)
+
+// BulkTarget is an Entity selected by a --filter
+type BulkTarget struct {
+	ID string
+	// Description is shown in the list of matches, e.g. the name of the Entity
+	Description string
+}
+
+// RunBulk prints the targets, asks for confirmation unless yes is set or this
+// is a dry run, and then runs fn for every target in parallel. action is used
+// for the summary, e.g. "Deleted", entities names the targets, e.g. "Resources".
+func RunBulk(targets []BulkTarget, action, entities string, yes bool, fn func(id string) error) error {
+	fmt.Printf("The filter matches %v %v:\n", len(targets), entities)
+	for _, t := range targets {
+		fmt.Printf("  %v (%v)\n", shellescape.StripUnsafe(t.Description), t.ID)
+	}
+
+	if !yes && !DryRun() {
+		ok, err := Confirm("Continue?")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	ids := make([]string, len(targets))
+	for i, t := range targets {
+		ids[i] = t.ID
+	}
+	errs := ForEachParallel(ids, fn)
+	return PrintBulkSummary(action, entities, len(ids), errs)
+}
+
+// Confirm asks a yes/no question on the terminal, it fails if there is no
+// terminal to ask on
+func Confirm(question string) (bool, error) {
+	answer, err := ask(fmt.Sprintf("%v [y/N]: ", question))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// ConfirmTyped asks to type expected on the terminal to confirm a destructive
+// action, it fails if there is no terminal to ask on
+func ConfirmTyped(question, expected string) (bool, error) {
+	// Compare against what is shown, the answer is trimmed as well
+	expected = strings.TrimSpace(shellescape.StripUnsafe(expected))
+	answer, err := ask(fmt.Sprintf("%v\nType %q to confirm: ", question, expected))
+	if err != nil {
+		return false, err
+	}
+	return answer == expected, nil
+}
+
+func ask(prompt string) (string, error) {
+	if agentRun != nil {
+		answer, err := agentRun.Prompt(prompt, false)
+		return strings.TrimSpace(answer), err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("confirmation required but stdin is not a terminal, use --yes to confirm")
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("reading confirmation: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestRunBulk(t *testing.T) {
+	viper.Set("workers", 2)
+	defer viper.Set("workers", nil)
+
+	targets := []BulkTarget{{ID: "a", Description: "first"}, {ID: "b", Description: "second"}}
+	var mu sync.Mutex
+	done := []string{}
+	err := RunBulk(targets, "Deleted", "Resources", true, func(id string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		done = append(done, id)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 {
+		t.Errorf("ran for %v, want a and b", done)
+	}
+
+	// Without --yes confirmation is required, which needs a terminal
+	err = RunBulk(targets, "Deleted", "Resources", false, func(id string) error {
+		t.Errorf("ran for %v without confirmation", id)
+		return nil
+	})
+	if err == nil {
+		t.Error("expected error without confirmation")
+	}
+}
+
+func TestConfirmTypedRequiresTerminal(t *testing.T) {
+	ok, err := ConfirmTyped("Delete?", "Prod")
+	if err == nil || ok {
+		t.Errorf("ConfirmTyped without a terminal = %v, %v, want an error", ok, err)
+	}
+}
+
+func TestConfirmTypedComparesShownName(t *testing.T) {
+	var shown string
+	SetAgentRun(&AgentRun{Prompt: func(prompt string, secret bool) (string, error) {
+		shown = prompt
+		return "Prod", nil
+	}})
+	defer SetAgentRun(nil)
+
+	ok, err := ConfirmTyped("Delete?", "Pro\x1bd")
+	if err != nil || !ok {
+		t.Errorf("ConfirmTyped with the shown name = %v, %v, want true", ok, err)
+	}
+	if shown != "Delete?\nType \"Prod\" to confirm: " {
+		t.Errorf("prompt = %q", shown)
+	}
+}
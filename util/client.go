@@ -19,6 +19,9 @@ import (
 
 // ReadPassword reads a Password interactively or via Pipe
 func ReadPassword(prompt string) (string, error) {
+	if agentRun != nil {
+		return agentRun.Prompt(prompt, true)
+	}
 	fd := int(os.Stdin.Fd())
 	var pass string
 	if term.IsTerminal(fd) {
@@ -44,8 +47,9 @@ func ReadPassword(prompt string) (string, error) {
 // SaveSessionKeysAndLogout saves any pending session keys to the server and then logs out.
 // This should be used instead of client.Logout() to ensure session keys are persisted.
 func SaveSessionKeysAndLogout(ctx context.Context, client *api.Client) {
-	// Save any pending session keys that were discovered during decryption
-	if count := client.GetPendingSessionKeysCount(); count > 0 {
+	// Save any pending session keys that were discovered during decryption,
+	// unless this is a dry run which must not write anything
+	if count := client.GetPendingSessionKeysCount(); count > 0 && !DryRun() {
 		saved, err := client.SavePendingSessionKeys(ctx)
 		if err != nil {
 			// Log but don't fail - session keys can be re-discovered on next access
@@ -54,18 +58,22 @@ func SaveSessionKeysAndLogout(ctx context.Context, client *api.Client) {
 			fmt.Fprintf(os.Stderr, "Saved %d session keys to server\n", saved)
 		}
 	}
+	// A cached session is kept for the next invocation, the agent keeps its client logged in
+	if _, ok := resumedClients.Load(client); ok || agentRun != nil {
+		return
+	}
 	client.Logout(ctx)
 }
 
 // GetClient gets a Logged in Passbolt Client
 func GetClient(ctx context.Context) (*api.Client, error) {
-	serverAddress := viper.GetString("serverAddress")
-	if serverAddress == "" {
+	if agentRun != nil {
+		return agentRun.Client(ctx)
+	}
+	if viper.GetString("serverAddress") == "" {
 		return nil, fmt.Errorf("serverAddress is not defined")
 	}
-
-	userPrivateKey := viper.GetString("userPrivateKey")
-	if userPrivateKey == "" {
+	if viper.GetString("userPrivateKey") == "" {
 		return nil, fmt.Errorf("userPrivateKey is not defined")
 	}
 
@@ -80,11 +88,32 @@ func GetClient(ctx context.Context) (*api.Client, error) {
 		userPassword = cliPassword
 		fmt.Println()
 	}
+	return LoginClient(ctx, userPassword)
+}
+
+// LoginClient logs in with the configured Server and Private Key, unlocked with userPassword
+func LoginClient(ctx context.Context, userPassword string) (*api.Client, error) {
+	serverAddress := viper.GetString("serverAddress")
+	if serverAddress == "" {
+		return nil, fmt.Errorf("serverAddress is not defined")
+	}
+
+	userPrivateKey := viper.GetString("userPrivateKey")
+	if userPrivateKey == "" {
+		return nil, fmt.Errorf("userPrivateKey is not defined")
+	}
+	if userPassword == "" {
+		return nil, fmt.Errorf("empty Password")
+	}
 
 	httpClient, err := GetHTTPClient()
 	if err != nil {
 		return nil, err
 	}
+	var transport *sessionTransport
+	if SessionCacheEnabled() {
+		transport = addSessionTransport(httpClient)
+	}
 	client, err := api.NewClient(httpClient, "", serverAddress, userPrivateKey, userPassword)
 	if err != nil {
 		return nil, fmt.Errorf("creating Client: %w", err)
@@ -121,26 +150,13 @@ func GetClient(ctx context.Context) (*api.Client, error) {
 					return http.Cookie{}, fmt.Errorf("reading TOTP: %w", err)
 				}
 				fmt.Printf("\n")
-				req := api.MFAChallengeResponse{
-					TOTP: code,
-				}
-				var raw *http.Response
-				raw, _, err = c.DoCustomRequestAndReturnRawResponseV5(ctx, "POST", "mfa/verify/totp.json", req, nil)
-				if err != nil {
-					var apiErr *api.APIError
-					if !errors.As(err, &apiErr) {
-						return http.Cookie{}, fmt.Errorf("doing MFA Challenge Response: %w", err)
-					}
-					fmt.Println("TOTP Verification Failed")
-				} else {
-					// MFA worked so lets find the cookie and return it
-					for _, cookie := range raw.Cookies() {
-						if cookie.Name == "passbolt_mfa" {
-							return *cookie, nil
-						}
-					}
-					return http.Cookie{}, fmt.Errorf("unable to find Passbolt MFA Cookie")
+				var cookie http.Cookie
+				cookie, err = verifyTOTP(ctx, c, code)
+				var apiErr *api.APIError
+				if err == nil || !errors.As(err, &apiErr) {
+					return cookie, err
 				}
+				fmt.Println("TOTP Verification Failed")
 			}
 			return http.Cookie{}, fmt.Errorf("failed MFA Challenge 3 times: %w", err)
 		}
@@ -156,14 +172,94 @@ func GetClient(ctx context.Context) (*api.Client, error) {
 			totpOffset = viper.GetDuration("totpOffset")
 		}
 
-		helper.AddMFACallbackTOTP(client, viper.GetUint("mfaRetrys"), viper.GetDuration("mfaDelay"), totpOffset, totpToken)
+		if SessionCacheEnabled() {
+			// helper.AddMFACallbackTOTP does not ask to remember the
+			// verification, which is required to cache it
+			addMFACallbackTOTP(client, viper.GetUint("mfaRetrys"), viper.GetDuration("mfaDelay"), totpOffset, totpToken)
+		} else {
+			helper.AddMFACallbackTOTP(client, viper.GetUint("mfaRetrys"), viper.GetDuration("mfaDelay"), totpOffset, totpToken)
+		}
 	case "none":
 	default:
 	}
 
+	if SessionCacheEnabled() {
+		resumed, cachedMFA := resumeSession(ctx, client, transport)
+		if resumed {
+			return client, nil
+		}
+		addCachedMFA(client, cachedMFA)
+		err = client.Login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("logging in: %w", err)
+		}
+		cacheSession(client, transport, cachedMFA)
+		return client, nil
+	}
+
 	err = client.Login(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("logging in: %w", err)
 	}
 	return client, nil
 }
+
+// verifyTOTP answers the MFA challenge with a TOTP code and returns the MFA
+// cookie. With the session cache enabled the Server is asked to remember the
+// verification, so the cookie can be cached.
+func verifyTOTP(ctx context.Context, c *api.Client, code string) (http.Cookie, error) {
+	var req any = api.MFAChallengeResponse{
+		TOTP: code,
+	}
+	if SessionCacheEnabled() {
+		req = rememberMFAChallengeResponse{
+			MFAChallengeResponse: api.MFAChallengeResponse{TOTP: code},
+			Remember:             true,
+		}
+	}
+	raw, _, err := c.DoCustomRequestAndReturnRawResponseV5(ctx, "POST", "mfa/verify/totp.json", req, nil)
+	if err != nil {
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) {
+			return http.Cookie{}, err
+		}
+		return http.Cookie{}, fmt.Errorf("doing MFA Challenge Response: %w", err)
+	}
+	// MFA worked so lets find the cookie and return it
+	for _, cookie := range raw.Cookies() {
+		if cookie.Name == mfaCookieName {
+			return *cookie, nil
+		}
+	}
+	return http.Cookie{}, fmt.Errorf("unable to find Passbolt MFA Cookie")
+}
+
+// addMFACallbackTOTP answers MFA challenges with codes generated from
+// token like helper.AddMFACallbackTOTP, but through verifyTOTP
+func addMFACallbackTOTP(client *api.Client, retrys uint, delay, offset time.Duration, token string) {
+	client.MFACallback = func(ctx context.Context, c *api.Client, res *api.APIResponse) (http.Cookie, error) {
+		totp, err := ParseTOTP(token)
+		if err != nil {
+			return http.Cookie{}, fmt.Errorf("parsing TOTP Token: %w", err)
+		}
+		for i := uint(0); i < retrys; i++ {
+			var code string
+			code, _, err = TOTPCode(totp, time.Now().Add(offset))
+			if err != nil {
+				return http.Cookie{}, fmt.Errorf("generating TOTP Code: %w", err)
+			}
+			var cookie http.Cookie
+			cookie, err = verifyTOTP(ctx, c, code)
+			var apiErr *api.APIError
+			if err == nil || !errors.As(err, &apiErr) {
+				return cookie, err
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return http.Cookie{}, ctx.Err()
+			}
+		}
+		return http.Cookie{}, fmt.Errorf("failed MFA Challenge %v times: %w", retrys, err)
+	}
+}
@@ -7,5 +7,10 @@ import (
 )
 
 func GetContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), viper.GetViper().GetDuration("timeout"))
+	parent := context.Background()
+	if agentRun != nil && agentRun.Context != nil {
+		// Stop when the CLI the agent runs this for goes away
+		parent = agentRun.Context
+	}
+	return context.WithTimeout(parent, viper.GetViper().GetDuration("timeout"))
 }
@@ -0,0 +1,62 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseEnvFile parses a dotenv file into KEY=VALUE pairs. Empty lines, lines
+// starting with # and an "export " prefix are ignored, and values can be
+// wrapped in single or double quotes.
+func ParseEnvFile(r io.Reader) ([]string, error) {
+	vars := []string{}
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %v: expected KEY=VALUE", line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		vars = append(vars, key+"="+value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// MergeEnv sets the KEY=VALUE pairs of vars in env, replacing existing keys
+// in place and appending new ones
+func MergeEnv(env, vars []string) []string {
+	merged := make([]string, len(env), len(env)+len(vars))
+	copy(merged, env)
+	index := make(map[string]int, len(merged))
+	for i, e := range merged {
+		key, _, _ := strings.Cut(e, "=")
+		index[key] = i
+	}
+	for _, v := range vars {
+		key, _, _ := strings.Cut(v, "=")
+		if i, ok := index[key]; ok {
+			merged[i] = v
+			continue
+		}
+		index[key] = len(merged)
+		merged = append(merged, v)
+	}
+	return merged
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	got, err := ParseEnvFile(strings.NewReader(`
+# database
+DB_USER=passbolt://folder/Prod/DB#username
+export DB_PASS="passbolt://folder/Prod/DB"
+EMPTY=
+QUOTED='a = b'
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"DB_USER=passbolt://folder/Prod/DB#username",
+		"DB_PASS=passbolt://folder/Prod/DB",
+		"EMPTY=",
+		"QUOTED=a = b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseEnvFile = %q, want %q", got, want)
+	}
+
+	if _, err := ParseEnvFile(strings.NewReader("NOT A PAIR\n")); err == nil {
+		t.Error("expected error for line without =")
+	}
+}
+
+func TestMergeEnv(t *testing.T) {
+	got := MergeEnv([]string{"A=1", "B=2"}, []string{"B=3", "C=4", "C=5"})
+	want := []string{"A=1", "B=3", "C=5"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeEnv = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+	"github.com/spf13/viper"
+)
+
+// DryRun reports whether the global --dry-run flag is set. Mutating commands
+// then resolve everything as usual but only print the API calls they would make.
+func DryRun() bool {
+	return viper.GetBool("dryRun")
+}
+
+// PrintDryRun prints an API call that a mutating command would make
+func PrintDryRun(method, path, format string, a ...any) {
+	fmt.Printf("[dry-run] %v %v: %v\n", method, path, shellescape.StripUnsafe(fmt.Sprintf(format, a...)))
+}
+
+// DescribeResource returns the name and ID of a Resource for dry-run output,
+// failing if it does not exist or cannot be decrypted.
+func DescribeResource(ctx context.Context, client *api.Client, id string) (string, error) {
+	_, name, _, _, _, _, err := helper.GetResource(ctx, client, id)
+	if err != nil {
+		return "", fmt.Errorf("getting Resource: %w", err)
+	}
+	return fmt.Sprintf("Resource %q (%v)", name, id), nil
+}
+
+// DescribeFolder returns the name and ID of a Folder for dry-run output, an
+// empty ID describes the root.
+func DescribeFolder(ctx context.Context, client *api.Client, id string) (string, error) {
+	if id == "" {
+		return "the root Folder", nil
+	}
+	_, name, err := helper.GetFolder(ctx, client, id)
+	if err != nil {
+		return "", fmt.Errorf("getting Folder: %w", err)
+	}
+	return fmt.Sprintf("Folder %q (%v)", name, id), nil
+}
+
+// DescribeUser returns the username and ID of a User for dry-run output
+func DescribeUser(ctx context.Context, client *api.Client, id string) (string, error) {
+	_, username, _, _, err := helper.GetUser(ctx, client, id)
+	if err != nil {
+		return "", fmt.Errorf("getting User: %w", err)
+	}
+	return fmt.Sprintf("User %q (%v)", username, id), nil
+}
+
+// DescribeGroup returns the name and ID of a Group for dry-run output
+func DescribeGroup(ctx context.Context, client *api.Client, id string) (string, error) {
+	name, _, err := helper.GetGroup(ctx, client, id)
+	if err != nil {
+		return "", fmt.Errorf("getting Group: %w", err)
+	}
+	return fmt.Sprintf("Group %q (%v)", name, id), nil
+}
+
+// DescribeShare describes sharing with the given Users and Groups for dry-run
+// output, resolving each of them.
+func DescribeShare(ctx context.Context, client *api.Client, users, groups []string, pType int) (string, error) {
+	aros := []string{}
+	for _, id := range users {
+		user, err := DescribeUser(ctx, client, id)
+		if err != nil {
+			return "", err
+		}
+		aros = append(aros, user)
+	}
+	for _, id := range groups {
+		group, err := DescribeGroup(ctx, client, id)
+		if err != nil {
+			return "", err
+		}
+		aros = append(aros, group)
+	}
+	return fmt.Sprintf("%v as %v", aros, PermissionTypeName(pType)), nil
+}
+
+// PermissionTypeName returns the display name of a Permission Type
+func PermissionTypeName(pType int) string {
+	switch pType {
+	case 1:
+		return "Read Only"
+	case 7:
+		return "Can Update"
+	case 15:
+		return "Owner"
+	case -1:
+		return "Removed"
+	}
+	return fmt.Sprintf("Type %v", pType)
+}
+
+// FieldNames returns the sorted keys of a field map, used to show which
+// fields would change without printing their values
+func FieldNames(fields map[string]any) []string {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldNames_Sorted(t *testing.T) {
+	got := FieldNames(map[string]any{"uri": "x", "name": "y", "password": "z"})
+	if !reflect.DeepEqual(got, []string{"name", "password", "uri"}) {
+		t.Errorf("FieldNames = %v", got)
+	}
+}
+
+func TestPermissionTypeName(t *testing.T) {
+	for pType, want := range map[int]string{1: "Read Only", 7: "Can Update", 15: "Owner", 3: "Type 3"} {
+		if got := PermissionTypeName(pType); got != want {
+			t.Errorf("PermissionTypeName(%v) = %q, want %q", pType, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package util
+
+import "github.com/passbolt/go-passbolt/api"
+
+// FolderPaths returns the path of names from the root to each Folder, keyed
+// by Folder ID. Folders whose parent is not in the list (e.g. not shared with
+// us) start a new path at the root.
+func FolderPaths(folders []api.Folder) map[string][]string {
+	byID := make(map[string]api.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+
+	paths := make(map[string][]string, len(folders))
+	var resolve func(id string, depth int) []string
+	resolve = func(id string, depth int) []string {
+		if path, ok := paths[id]; ok {
+			return path
+		}
+		folder, ok := byID[id]
+		// depth guards against a cyclic hierarchy from a malformed response
+		if !ok || depth > len(folders) {
+			return nil
+		}
+		parent := resolve(folder.FolderParentID, depth+1)
+		path := make([]string, len(parent), len(parent)+1)
+		copy(path, parent)
+		path = append(path, folder.Name)
+		paths[id] = path
+		return path
+	}
+	for id := range byID {
+		resolve(id, 0)
+	}
+	return paths
+}
@@ -0,0 +1,214 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+// ImportEntry is a Resource read from a foreign format, independent of the
+// Resource Type it will be created as.
+type ImportEntry struct {
+	Name         string
+	Username     string
+	URIs         []string
+	Password     string
+	Description  string
+	TOTP         map[string]any
+	CustomFields []ImportCustomField
+}
+
+// ImportCustomField is a key/value pair that becomes a v5 custom field
+type ImportCustomField struct {
+	Key   string
+	Value string
+}
+
+// CustomFields correlates the v5 custom fields of a Resource, taking the key
+// from metadata.custom_fields[].metadata_key and the value from
+// secret.custom_fields[].secret_value, matched by id.
+func CustomFields(metadata, secretFields map[string]any) []ImportCustomField {
+	metaList, _ := metadata["custom_fields"].([]any)
+	if len(metaList) == 0 {
+		return nil
+	}
+	secretList, _ := secretFields["custom_fields"].([]any)
+	valueByID := make(map[string]string, len(secretList))
+	for _, item := range secretList {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].(string)
+		val, _ := m["secret_value"].(string)
+		if id != "" {
+			valueByID[id] = val
+		}
+	}
+
+	fields := []ImportCustomField{}
+	for _, item := range metaList {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, _ := m["id"].(string)
+		key, _ := m["metadata_key"].(string)
+		if key == "" {
+			continue
+		}
+		fields = append(fields, ImportCustomField{Key: key, Value: valueByID[id]})
+	}
+	return fields
+}
+
+// ImportEntryFields picks the Resource Type for an entry and builds the
+// metadata and secret field maps for helper.CreateResourceGeneric, which
+// routes uri and description to the right side for the chosen type.
+func ImportEntryFields(e ImportEntry, isV5 bool) (string, map[string]any, map[string]any) {
+	name := e.Name
+	if name == "" {
+		name = "(no name)"
+	}
+	metadata := map[string]any{
+		"name": name,
+	}
+	if e.Username != "" {
+		metadata["username"] = e.Username
+	}
+	if len(e.URIs) > 0 {
+		metadata["uri"] = e.URIs[0]
+		if isV5 && len(e.URIs) > 1 {
+			uris := make([]any, len(e.URIs))
+			for i := range e.URIs {
+				uris[i] = e.URIs[i]
+			}
+			metadata["uris"] = uris
+		}
+	}
+	if e.Description != "" {
+		metadata["description"] = e.Description
+	}
+
+	secret := map[string]any{}
+	if e.Password != "" || e.TOTP == nil {
+		secret["password"] = e.Password
+	}
+	if e.TOTP != nil {
+		secret["totp"] = e.TOTP
+	}
+
+	if !isV5 {
+		switch {
+		case e.TOTP != nil && e.Password == "" && e.Description == "":
+			return "totp", metadata, secret
+		case e.TOTP != nil:
+			return "password-description-totp", metadata, secret
+		default:
+			return "password-and-description", metadata, secret
+		}
+	}
+
+	if len(e.CustomFields) > 0 {
+		metaList := make([]any, 0, len(e.CustomFields))
+		secretList := make([]any, 0, len(e.CustomFields))
+		for _, field := range e.CustomFields {
+			id := uuid.NewString()
+			metaList = append(metaList, map[string]any{"id": id, "type": "text", "metadata_key": field.Key})
+			secretList = append(secretList, map[string]any{"id": id, "type": "text", "secret_value": field.Value})
+		}
+		metadata["custom_fields"] = metaList
+		secret["custom_fields"] = secretList
+	}
+
+	switch {
+	case e.TOTP != nil && e.Password == "" && e.Description == "" && len(e.CustomFields) == 0:
+		return "v5-totp-standalone", metadata, secret
+	case e.TOTP != nil:
+		return "v5-default-with-totp", metadata, secret
+	default:
+		return "v5-default", metadata, secret
+	}
+}
+
+// CreateImportEntry creates a Resource for an ImportEntry in the given Folder
+// and returns its ID. Custom fields are dropped with a warning on servers
+// without v5 Resource Types.
+func CreateImportEntry(ctx context.Context, client *api.Client, folderParentID string, e ImportEntry) (string, error) {
+	isV5 := DefaultResourceTypeIsV5(client)
+	if !isV5 && len(e.CustomFields) > 0 {
+		fmt.Printf("\nDropping %v Custom Fields of Entry %v, they require a v5 Resource Type\n", len(e.CustomFields), e.Name)
+	}
+	slug, metadata, secret := ImportEntryFields(e, isV5)
+	if DryRun() {
+		PrintDryRun("POST", "/resources.json", "create Resource %q of type %v in Folder %v, metadata fields %v, secret fields %v",
+			metadata["name"], slug, folderParentID, FieldNames(metadata), FieldNames(secret))
+		return "", nil
+	}
+	return helper.CreateResourceGeneric(ctx, client, slug, folderParentID, metadata, secret)
+}
+
+// CreateImportFolder creates a Folder for an import and returns its ID. In a
+// dry run it only prints the call and returns a placeholder ID naming the
+// Folder, so that its content can still be attributed in the output.
+func CreateImportFolder(ctx context.Context, client *api.Client, folderParentID, name string) (string, error) {
+	if DryRun() {
+		PrintDryRun("POST", "/folders.json", "create Folder %q in Folder %v", name, folderParentID)
+		return fmt.Sprintf("<new %v>", name), nil
+	}
+	return helper.CreateFolder(ctx, client, folderParentID, name)
+}
+
+// FolderPathCreator creates nested Folders from slash separated paths below a
+// base Folder, creating each path only once per import.
+type FolderPathCreator struct {
+	client         *api.Client
+	folderParentID string
+	ids            map[string]string
+}
+
+// NewFolderPathCreator returns a FolderPathCreator that creates Folders below
+// folderParentID (empty for the root).
+func NewFolderPathCreator(client *api.Client, folderParentID string) *FolderPathCreator {
+	return &FolderPathCreator{
+		client:         client,
+		folderParentID: folderParentID,
+		ids:            map[string]string{},
+	}
+}
+
+// Created returns how many Folders have been created so far
+func (f *FolderPathCreator) Created() int {
+	return len(f.ids)
+}
+
+// Ensure returns the ID of the Folder at path, creating missing Folders along
+// the way. An empty path returns the base Folder.
+func (f *FolderPathCreator) Ensure(ctx context.Context, path []string) (string, error) {
+	names := []string{}
+	for _, name := range path {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	parentID := f.folderParentID
+	for i := range names {
+		key := strings.Join(names[:i+1], "/")
+		if id, ok := f.ids[key]; ok {
+			parentID = id
+			continue
+		}
+		id, err := CreateImportFolder(ctx, f.client, parentID, names[i])
+		if err != nil {
+			return "", fmt.Errorf("creating Folder %v: %w", key, err)
+		}
+		f.ids[key] = id
+		parentID = id
+	}
+	return parentID, nil
+}
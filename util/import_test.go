@@ -0,0 +1,48 @@
+package util
+
+import "testing"
+
+func TestImportEntryFields_TypeSelection(t *testing.T) {
+	totp := map[string]any{"secret_key": "ABC"}
+	cases := []struct {
+		name  string
+		entry ImportEntry
+		isV5  bool
+		want  string
+	}{
+		{"v5 password", ImportEntry{Name: "a", Password: "p"}, true, "v5-default"},
+		{"v5 password and totp", ImportEntry{Name: "a", Password: "p", TOTP: totp}, true, "v5-default-with-totp"},
+		{"v5 totp only", ImportEntry{Name: "a", TOTP: totp}, true, "v5-totp-standalone"},
+		{"v5 totp with custom fields", ImportEntry{Name: "a", TOTP: totp, CustomFields: []ImportCustomField{{"k", "v"}}}, true, "v5-default-with-totp"},
+		{"v4 password", ImportEntry{Name: "a", Password: "p"}, false, "password-and-description"},
+		{"v4 password and totp", ImportEntry{Name: "a", Password: "p", TOTP: totp}, false, "password-description-totp"},
+		{"v4 totp only", ImportEntry{Name: "a", TOTP: totp}, false, "totp"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, _ := ImportEntryFields(tc.entry, tc.isV5)
+			if got != tc.want {
+				t.Errorf("type = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestImportEntryFields_CustomFieldIDsMatch(t *testing.T) {
+	e := ImportEntry{Name: "a", Password: "p", CustomFields: []ImportCustomField{{"env", "prod"}}}
+	_, metadata, secret := ImportEntryFields(e, true)
+
+	metaList, _ := metadata["custom_fields"].([]any)
+	secretList, _ := secret["custom_fields"].([]any)
+	if len(metaList) != 1 || len(secretList) != 1 {
+		t.Fatalf("custom_fields = %v / %v, want one entry each", metaList, secretList)
+	}
+	m := metaList[0].(map[string]any)
+	s := secretList[0].(map[string]any)
+	if m["id"] != s["id"] {
+		t.Errorf("metadata id %v != secret id %v", m["id"], s["id"])
+	}
+	if m["metadata_key"] != "env" || s["secret_value"] != "prod" {
+		t.Errorf("custom field = %v / %v", m, s)
+	}
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MaskReplacement replaces secrets in masked output
+const MaskReplacement = "***"
+
+// MaskingWriter replaces every occurrence of the secrets in the written
+// data with MaskReplacement. Data that could be the start of a secret is
+// held back until it can be decided, so Flush has to be called at the end.
+type MaskingWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	secrets [][]byte
+	buf     []byte
+}
+
+// minMaskLineLength is how long a line of a multi line secret has to be to
+// be masked on its own
+const minMaskLineLength = 8
+
+// NewMaskingWriter returns a MaskingWriter writing to w. Distinctive lines of
+// multi line secrets are additionally masked on their own, as tools often
+// print them that way.
+func NewMaskingWriter(w io.Writer, secrets []string) *MaskingWriter {
+	unique := map[string]bool{}
+	for _, s := range secrets {
+		unique[s] = true
+		if strings.Contains(s, "\n") {
+			for _, line := range strings.Split(s, "\n") {
+				line = strings.TrimSpace(line)
+				if distinctiveLine(line) {
+					unique[line] = true
+				}
+			}
+		}
+	}
+	delete(unique, "")
+
+	m := &MaskingWriter{w: w}
+	for s := range unique {
+		m.secrets = append(m.secrets, []byte(s))
+	}
+	// Prefer the longest match, so a secret containing another is masked whole
+	sort.Slice(m.secrets, func(i, j int) bool {
+		return len(m.secrets[i]) > len(m.secrets[j])
+	})
+	return m
+}
+
+// distinctiveLine returns if a line of a secret is unlikely to appear in other
+// output. Short lines, lines of only punctuation like "}," in JSON and PEM
+// armor lines like "-----END PRIVATE KEY-----" are not.
+func distinctiveLine(line string) bool {
+	if len(line) < minMaskLineLength || strings.HasPrefix(line, "-----") {
+		return false
+	}
+	return strings.IndexFunc(line, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+}
+
+// Write masks p and writes everything that can't be part of a secret anymore
+func (m *MaskingWriter) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.buf = append(m.buf, p...)
+	out, rest := m.mask(m.buf, false)
+	m.buf = append(m.buf[:0], rest...)
+	if len(out) > 0 {
+		if _, err := m.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes the held back data
+func (m *MaskingWriter) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out, _ := m.mask(m.buf, true)
+	m.buf = m.buf[:0]
+	if len(out) == 0 {
+		return nil
+	}
+	_, err := m.w.Write(out)
+	return err
+}
+
+// mask returns the masked data that is safe to write and the remainder that
+// could still become a secret, unless final is set
+func (m *MaskingWriter) mask(data []byte, final bool) ([]byte, []byte) {
+	var out bytes.Buffer
+	i := 0
+scan:
+	for i < len(data) {
+		rest := data[i:]
+		for _, s := range m.secrets {
+			if bytes.HasPrefix(rest, s) {
+				out.WriteString(MaskReplacement)
+				i += len(s)
+				continue scan
+			}
+		}
+		if !final {
+			for _, s := range m.secrets {
+				if len(rest) < len(s) && bytes.HasPrefix(s, rest) {
+					return out.Bytes(), rest
+				}
+			}
+		}
+		out.WriteByte(data[i])
+		i++
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,202 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TOTPURI renders the totp secret field of a Resource as an otpauth:// URI.
+// It returns an empty string if the secret has no secret_key, as no valid URI
+// can be built without one.
+func TOTPURI(totp map[string]any, issuer, accountName string) string {
+	secretKey, _ := totp["secret_key"].(string)
+	if secretKey == "" {
+		return ""
+	}
+	algorithm, _ := totp["algorithm"].(string)
+	digits := intField(totp, "digits")
+	period := intField(totp, "period")
+
+	v := url.Values{}
+	v.Set("secret", secretKey)
+	v.Set("period", strconv.FormatUint(uint64(period), 10))
+	v.Set("algorithm", algorithm)
+	v.Set("digits", fmt.Sprint(digits))
+	v.Set("issuer", issuer)
+
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: encodeQuery(v),
+	}
+	return u.String()
+}
+
+// ParseTOTPURI parses an otpauth://totp URI as written by TOTPURI (and most
+// other password managers) into a Passbolt totp secret field.
+func ParseTOTPURI(raw string) (map[string]any, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing otp uri: %w", err)
+	}
+	if u.Scheme != "otpauth" {
+		return nil, fmt.Errorf("unsupported otp uri scheme %q", u.Scheme)
+	}
+	if u.Host != "totp" {
+		return nil, fmt.Errorf("unsupported otp type %q", u.Host)
+	}
+
+	query := u.Query()
+	secretKey := strings.ToUpper(strings.ReplaceAll(query.Get("secret"), " ", ""))
+	if secretKey == "" {
+		return nil, fmt.Errorf("otp uri has no secret")
+	}
+
+	algorithm := strings.ToUpper(query.Get("algorithm"))
+	if algorithm == "" {
+		algorithm = "SHA1"
+	}
+
+	digits := 6
+	if d := query.Get("digits"); d != "" {
+		digits, err = strconv.Atoi(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid otp digits %q: %w", d, err)
+		}
+	}
+
+	period := 30
+	if p := query.Get("period"); p != "" {
+		period, err = strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid otp period %q: %w", p, err)
+		}
+	}
+
+	return map[string]any{
+		"secret_key": secretKey,
+		"algorithm":  algorithm,
+		"digits":     digits,
+		"period":     period,
+	}, nil
+}
+
+// ParseTOTP parses either an otpauth://totp URI or a bare base32 secret, as
+// some password managers only store the latter, using the default algorithm,
+// digits and period for bare secrets.
+func ParseTOTP(value string) (map[string]any, error) {
+	if !strings.HasPrefix(value, "otpauth://") {
+		value = "otpauth://totp/?secret=" + url.QueryEscape(value)
+	}
+	return ParseTOTPURI(value)
+}
+
+// TOTPCode generates the RFC 6238 code of a Passbolt totp secret field at the
+// given time and returns how long the code stays valid. Missing parameters
+// default to SHA1, 6 digits and a 30 second period.
+func TOTPCode(totp map[string]any, when time.Time) (string, time.Duration, error) {
+	secretKey, _ := totp["secret_key"].(string)
+	secretKey = strings.TrimRight(strings.ToUpper(strings.ReplaceAll(secretKey, " ", "")), "=")
+	if secretKey == "" {
+		return "", 0, fmt.Errorf("totp has no secret_key")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secretKey)
+	if err != nil {
+		return "", 0, fmt.Errorf("decoding totp secret_key: %w", err)
+	}
+
+	digits := intField(totp, "digits")
+	if digits == 0 {
+		digits = 6
+	}
+	if digits < 1 || digits > 10 {
+		return "", 0, fmt.Errorf("unsupported totp digits %v", digits)
+	}
+	period := intField(totp, "period")
+	if period == 0 {
+		period = 30
+	}
+	if period < 0 {
+		return "", 0, fmt.Errorf("invalid totp period %v", period)
+	}
+
+	var h func() hash.Hash
+	algorithm, _ := totp["algorithm"].(string)
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA1":
+		h = sha1.New
+	case "SHA256":
+		h = sha256.New
+	case "SHA512":
+		h = sha512.New
+	default:
+		return "", 0, fmt.Errorf("unsupported totp algorithm %q", algorithm)
+	}
+
+	unix := when.Unix()
+	counter := uint64(unix / int64(period))
+	mac := hmac.New(h, key)
+	binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	value := uint64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+
+	mod := uint64(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	remaining := time.Duration(int64(period)-unix%int64(period)) * time.Second
+	return fmt.Sprintf("%0*d", digits, value%mod), remaining, nil
+}
+
+// intField reads a number from a decoded JSON map, which may hold it as
+// float64 (from the server) or int (when built locally).
+func intField(m map[string]any, key string) int {
+	switch v := m[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// EncodeQuery is a copy-paste of url.Values.Encode, except it uses %20 instead
+// of + to encode spaces. This is necessary to correctly render spaces in some
+// authenticator apps, like Google Authenticator.
+func encodeQuery(v url.Values) string {
+	if v == nil {
+		return ""
+	}
+	var buf strings.Builder
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		vs := v[k]
+		keyEscaped := url.PathEscape(k) // changed from url.QueryEscape
+		for _, v := range vs {
+			if buf.Len() > 0 {
+				buf.WriteByte('&')
+			}
+			buf.WriteString(keyEscaped)
+			buf.WriteByte('=')
+			buf.WriteString(url.PathEscape(v)) // changed from url.QueryEscape
+		}
+	}
+	return buf.String()
+}
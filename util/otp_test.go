@@ -0,0 +1,202 @@
+package util
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// encodeQuery is a copy of url.Values.Encode that uses %20 instead of '+' for
+// spaces, so authenticator apps (Google Authenticator) parse otpauth URIs
+// correctly. These tests lock in that distinction.
+
+func TestEncodeQuery_Empty(t *testing.T) {
+	if got := encodeQuery(nil); got != "" {
+		t.Errorf("nil input = %q, want empty", got)
+	}
+	if got := encodeQuery(url.Values{}); got != "" {
+		t.Errorf("empty input = %q, want empty", got)
+	}
+}
+
+func TestEncodeQuery_SpacesUsePercent20(t *testing.T) {
+	v := url.Values{"issuer": {"My Service"}}
+	got := encodeQuery(v)
+	if !strings.Contains(got, "%20") {
+		t.Errorf("encodeQuery should encode space as %%20, got %q", got)
+	}
+	if strings.Contains(got, "+") {
+		t.Errorf("encodeQuery must not use + for spaces, got %q", got)
+	}
+}
+
+func TestEncodeQuery_MatchesStdlibAfterPlusSubstitution(t *testing.T) {
+	// For values with no plus characters, encodeQuery's output should be
+	// identical to url.Values.Encode after replacing '+' with '%20'.
+	v := url.Values{
+		"issuer": {"Acme"},
+		"period": {"30"},
+		"digits": {"6"},
+	}
+	got := encodeQuery(v)
+	std := strings.ReplaceAll(v.Encode(), "+", "%20")
+	if got != std {
+		t.Errorf("encodeQuery(%v) = %q, want %q", v, got, std)
+	}
+}
+
+func TestEncodeQuery_KeysAreSorted(t *testing.T) {
+	v := url.Values{
+		"zeta":  {"z"},
+		"alpha": {"a"},
+		"mu":    {"m"},
+	}
+	got := encodeQuery(v)
+	if !strings.HasPrefix(got, "alpha=a&") || !strings.HasSuffix(got, "&zeta=z") {
+		t.Errorf("expected keys in lexicographic order, got %q", got)
+	}
+}
+
+func TestEncodeQuery_MultiValueRetained(t *testing.T) {
+	v := url.Values{"tag": {"foo", "bar"}}
+	got := encodeQuery(v)
+	if got != "tag=foo&tag=bar" {
+		t.Errorf("multi-value encoding = %q, want tag=foo&tag=bar", got)
+	}
+}
+
+func TestEncodeQuery_DoesNotEscapeAmpersandOrEquals(t *testing.T) {
+	// encodeQuery uses url.PathEscape (not QueryEscape) to keep %20 for
+	// spaces (required by Google Authenticator). PathEscape does NOT escape
+	// '&' or '=', so values containing them produce an ambiguous query
+	// string. Acceptable for otpauth labels in practice (they don't contain
+	// either character) but a sharp edge — this test locks in current
+	// behavior so a future change to QueryEscape is a deliberate decision.
+	v := url.Values{"label": {"foo&bar=baz"}}
+	got := encodeQuery(v)
+	if got != "label=foo&bar=baz" {
+		t.Errorf("encodeQuery(%v) = %q, want label=foo&bar=baz (current behavior)", v, got)
+	}
+}
+
+func TestParseTOTPURI_ExportFormat(t *testing.T) {
+	// The URI layout written by getKeepassEntry must parse back losslessly.
+	got, err := ParseTOTPURI("otpauth://totp/My%20Service:alice?algorithm=SHA256&digits=8&issuer=My%20Service&period=60&secret=JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]any{"secret_key": "JBSWY3DPEHPK3PXP", "algorithm": "SHA256", "digits": 8, "period": 60}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestParseTOTPURI_Defaults(t *testing.T) {
+	got, err := ParseTOTPURI("otpauth://totp/alice?secret=jbsw%20y3dp")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["secret_key"] != "JBSWY3DP" {
+		t.Errorf("secret_key = %v, want normalised JBSWY3DP", got["secret_key"])
+	}
+	if got["algorithm"] != "SHA1" || got["digits"] != 6 || got["period"] != 30 {
+		t.Errorf("defaults = %v, want SHA1/6/30", got)
+	}
+}
+
+func TestParseTOTPURI_Invalid(t *testing.T) {
+	cases := []string{
+		"https://example.com/?secret=ABC",
+		"otpauth://hotp/alice?secret=ABC",
+		"otpauth://totp/alice",
+		"otpauth://totp/alice?secret=ABC&digits=six",
+	}
+	for _, in := range cases {
+		if _, err := ParseTOTPURI(in); err == nil {
+			t.Errorf("ParseTOTPURI(%q) should fail", in)
+		}
+	}
+}
+
+func TestTOTPURI_RoundTrip(t *testing.T) {
+	totp := map[string]any{"secret_key": "JBSWY3DPEHPK3PXP", "algorithm": "SHA256", "digits": float64(8), "period": float64(60)}
+	uri := TOTPURI(totp, "My Service", "alice")
+	if !strings.HasPrefix(uri, "otpauth://totp/My%20Service:alice?") {
+		t.Errorf("TOTPURI = %q", uri)
+	}
+	got, err := ParseTOTPURI(uri)
+	if err != nil {
+		t.Fatalf("ParseTOTPURI(%q): %v", uri, err)
+	}
+	if got["secret_key"] != "JBSWY3DPEHPK3PXP" || got["algorithm"] != "SHA256" || got["digits"] != 8 || got["period"] != 60 {
+		t.Errorf("round trip = %v", got)
+	}
+}
+
+func TestTOTPURI_MissingSecretKey(t *testing.T) {
+	if got := TOTPURI(map[string]any{"algorithm": "SHA1"}, "issuer", "account"); got != "" {
+		t.Errorf("TOTPURI without secret_key = %q, want empty", got)
+	}
+}
+
+func TestParseTOTP_BareSecret(t *testing.T) {
+	got, err := ParseTOTP("jbsw y3dp ehpk 3pxp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["secret_key"] != "JBSWY3DPEHPK3PXP" || got["digits"] != 6 || got["period"] != 30 || got["algorithm"] != "SHA1" {
+		t.Errorf("bare secret = %v", got)
+	}
+}
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	seeds := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	cases := []struct {
+		unix      int64
+		algorithm string
+		want      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1111111109, "SHA256", "68084774"},
+		{1111111109, "SHA512", "25091201"},
+	}
+	for _, c := range cases {
+		totp := map[string]any{
+			"secret_key": base32.StdEncoding.EncodeToString([]byte(seeds[c.algorithm])),
+			"algorithm":  c.algorithm,
+			"digits":     float64(8),
+			"period":     float64(30),
+		}
+		got, remaining, err := TOTPCode(totp, time.Unix(c.unix, 0))
+		if err != nil || got != c.want {
+			t.Errorf("TOTPCode(%v, %v) = %q, %v, want %q", c.algorithm, c.unix, got, err, c.want)
+		}
+		if want := time.Duration(30-c.unix%30) * time.Second; remaining != want {
+			t.Errorf("remaining = %v, want %v", remaining, want)
+		}
+	}
+}
+
+func TestTOTPCode_Defaults(t *testing.T) {
+	got, _, err := TOTPCode(map[string]any{"secret_key": "jbsw y3dp ehpk 3pxp"}, time.Unix(0, 0))
+	if err != nil || len(got) != 6 {
+		t.Errorf("TOTPCode with defaults = %q, %v", got, err)
+	}
+	if _, _, err := TOTPCode(map[string]any{"secret_key": "JBSWY3DPEHPK3PXP", "algorithm": "MD5"}, time.Now()); err == nil {
+		t.Error("expected error for unsupported algorithm")
+	}
+	if _, _, err := TOTPCode(map[string]any{}, time.Now()); err == nil {
+		t.Error("expected error without secret_key")
+	}
+}
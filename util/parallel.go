@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// ForEachParallel runs fn for every ID using the configured number of
+// workers and returns the errors keyed by the ID that failed
+func ForEachParallel(ids []string, fn func(id string) error) map[string]error {
+	numWorkers := int(viper.GetUint("workers"))
+	if len(ids) < numWorkers {
+		numWorkers = len(ids)
+	}
+
+	jobs := make(chan string, len(ids))
+	var mu sync.Mutex
+	errs := map[string]error{}
+
+	var wg sync.WaitGroup
+	for w := 0; w < numWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				if err := fn(id); err != nil {
+					mu.Lock()
+					errs[id] = err
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+	wg.Wait()
+	return errs
+}
+
+// PrintBulkSummary prints how many of total entities succeeded, e.g.
+// "Deleted 3 of 4 Resources", and lists the failed ones on stderr. It returns
+// an error if any failed.
+func PrintBulkSummary(action, entities string, total int, errs map[string]error) error {
+	if !DryRun() {
+		fmt.Printf("%v %v of %v %v\n", action, total-len(errs), total, entities)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(errs))
+	for id := range errs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Fprintf(os.Stderr, "  %v: %v\n", id, errs[id])
+	}
+	return fmt.Errorf("%v of %v failed", len(errs), total)
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestForEachParallel(t *testing.T) {
+	viper.Set("workers", 4)
+	defer viper.Set("workers", nil)
+
+	ids := []string{}
+	for i := 0; i < 20; i++ {
+		ids = append(ids, fmt.Sprint(i))
+	}
+	var calls atomic.Int32
+	errs := ForEachParallel(ids, func(id string) error {
+		calls.Add(1)
+		if id == "3" || id == "17" {
+			return fmt.Errorf("failed %v", id)
+		}
+		return nil
+	})
+	if calls.Load() != 20 {
+		t.Errorf("fn called %v times, want 20", calls.Load())
+	}
+	if len(errs) != 2 || errs["3"] == nil || errs["17"] == nil {
+		t.Errorf("errs = %v, want errors for 3 and 17", errs)
+	}
+
+	if errs := ForEachParallel(nil, func(string) error { return nil }); len(errs) != 0 {
+		t.Errorf("errs = %v for no IDs", errs)
+	}
+}
@@ -0,0 +1,294 @@
+package util
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/viper"
+)
+
+// Character classes of the Passbolt password generator
+const (
+	CharsUpper       = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	CharsLower       = "abcdefghijklmnopqrstuvwxyz"
+	CharsDigit       = "0123456789"
+	CharsParenthesis = "{([|])}"
+	CharsChar1       = "#$%&@^~"
+	CharsChar2       = ".,:;"
+	CharsChar3       = "'\"`"
+	CharsChar4       = "/\\_-"
+	CharsChar5       = "<*+!?="
+	// CharsLookAlike are excluded with exclude_look_alike_chars
+	CharsLookAlike = "Ol|I01"
+)
+
+// PasswordGeneratorSettings are the password settings of a PasswordPolicy
+type PasswordGeneratorSettings struct {
+	Length                int  `json:"length"`
+	MaskUpper             bool `json:"mask_upper"`
+	MaskLower             bool `json:"mask_lower"`
+	MaskDigit             bool `json:"mask_digit"`
+	MaskParenthesis       bool `json:"mask_parenthesis"`
+	MaskEmoji             bool `json:"mask_emoji"`
+	MaskChar1             bool `json:"mask_char1"`
+	MaskChar2             bool `json:"mask_char2"`
+	MaskChar3             bool `json:"mask_char3"`
+	MaskChar4             bool `json:"mask_char4"`
+	MaskChar5             bool `json:"mask_char5"`
+	ExcludeLookAlikeChars bool `json:"exclude_look_alike_chars"`
+	MinLength             int  `json:"min_length"`
+	MaxLength             int  `json:"max_length"`
+}
+
+// PassphraseGeneratorSettings are the passphrase settings of a PasswordPolicy
+type PassphraseGeneratorSettings struct {
+	Words         int    `json:"words"`
+	WordSeparator string `json:"word_separator"`
+	// WordCase is one of lowercase, uppercase or camelcase
+	WordCase string `json:"word_case"`
+	MinWords int    `json:"min_words"`
+	MaxWords int    `json:"max_words"`
+}
+
+// PasswordPolicy is the Password Policy of an Organization
+type PasswordPolicy struct {
+	// DefaultGenerator is either password or passphrase
+	DefaultGenerator            string                      `json:"default_generator"`
+	PasswordGeneratorSettings   PasswordGeneratorSettings   `json:"password_generator_settings"`
+	PassphraseGeneratorSettings PassphraseGeneratorSettings `json:"passphrase_generator_settings"`
+}
+
+// DefaultPasswordPolicy is used if the Server has no Password Policy, it matches the Passbolt defaults
+var DefaultPasswordPolicy = PasswordPolicy{
+	DefaultGenerator: "password",
+	PasswordGeneratorSettings: PasswordGeneratorSettings{
+		Length:                18,
+		MaskUpper:             true,
+		MaskLower:             true,
+		MaskDigit:             true,
+		MaskParenthesis:       true,
+		MaskChar1:             true,
+		MaskChar2:             true,
+		MaskChar3:             true,
+		MaskChar4:             true,
+		MaskChar5:             true,
+		ExcludeLookAlikeChars: true,
+		MinLength:             8,
+		MaxLength:             128,
+	},
+	PassphraseGeneratorSettings: PassphraseGeneratorSettings{
+		Words:         9,
+		WordSeparator: " ",
+		WordCase:      "lowercase",
+		MinWords:      4,
+		MaxWords:      40,
+	},
+}
+
+// GetPasswordPolicy returns the Password Policy of the Organization. Servers
+// without the Password Policies Plugin get the DefaultPasswordPolicy.
+func GetPasswordPolicy(ctx context.Context, client *api.Client) (PasswordPolicy, error) {
+	raw, resp, err := client.DoCustomRequestAndReturnRawResponseV5(ctx, "GET", "password-policies/settings.json", nil, nil)
+	if err != nil {
+		// Older Servers don't have the endpoint, any other error could
+		// hide a stricter Policy
+		if raw == nil || raw.StatusCode != http.StatusNotFound {
+			return PasswordPolicy{}, fmt.Errorf("getting Password Policy: %w", err)
+		}
+		if viper.GetBool("debug") {
+			fmt.Fprintf(os.Stderr, "Using the default Password Policy: %v\n", err)
+		}
+		return DefaultPasswordPolicy, nil
+	}
+	policy := DefaultPasswordPolicy
+	err = json.Unmarshal(resp.Body, &policy)
+	if err != nil {
+		return PasswordPolicy{}, fmt.Errorf("parsing Password Policy: %w", err)
+	}
+	return policy, nil
+}
+
+// Check returns an error if the length is not allowed by the settings
+func (s PasswordGeneratorSettings) Check() error {
+	if s.MinLength > 0 && s.Length < s.MinLength {
+		return fmt.Errorf("the Password Policy requires a length of at least %v", s.MinLength)
+	}
+	if s.MaxLength > 0 && s.Length > s.MaxLength {
+		return fmt.Errorf("the Password Policy allows a length of at most %v", s.MaxLength)
+	}
+	return nil
+}
+
+// Check returns an error if the number of words is not allowed by the settings
+func (s PassphraseGeneratorSettings) Check() error {
+	if s.MinWords > 0 && s.Words < s.MinWords {
+		return fmt.Errorf("the Password Policy requires at least %v words", s.MinWords)
+	}
+	if s.MaxWords > 0 && s.Words > s.MaxWords {
+		return fmt.Errorf("the Password Policy allows at most %v words", s.MaxWords)
+	}
+	return nil
+}
+
+// emojis are the emoticons of the Unicode Emoticons block
+func emojis() string {
+	var sb strings.Builder
+	for r := rune(0x1F600); r <= 0x1F64F; r++ {
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// GeneratePassword generates a random Password with at least one character of
+// each selected class, exclude lists additional characters not to use
+func GeneratePassword(s PasswordGeneratorSettings, exclude string) (string, error) {
+	if s.ExcludeLookAlikeChars {
+		exclude += CharsLookAlike
+	}
+	classes := []string{}
+	for _, c := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{s.MaskUpper, CharsUpper},
+		{s.MaskLower, CharsLower},
+		{s.MaskDigit, CharsDigit},
+		{s.MaskParenthesis, CharsParenthesis},
+		{s.MaskChar1, CharsChar1},
+		{s.MaskChar2, CharsChar2},
+		{s.MaskChar3, CharsChar3},
+		{s.MaskChar4, CharsChar4},
+		{s.MaskChar5, CharsChar5},
+		{s.MaskEmoji, emojis()},
+	} {
+		if !c.enabled {
+			continue
+		}
+		chars := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(exclude, r) {
+				return -1
+			}
+			return r
+		}, c.chars)
+		if chars != "" {
+			classes = append(classes, chars)
+		}
+	}
+	if len(classes) == 0 {
+		return "", fmt.Errorf("no characters left to generate a Password from")
+	}
+	if s.Length < 1 {
+		return "", fmt.Errorf("invalid Password length %v", s.Length)
+	}
+
+	password := make([]rune, 0, s.Length)
+	// Include every class once if the length allows it
+	for _, class := range classes {
+		if len(password) == s.Length {
+			break
+		}
+		r, err := randomRune([]rune(class))
+		if err != nil {
+			return "", err
+		}
+		password = append(password, r)
+	}
+	all := []rune(strings.Join(classes, ""))
+	for len(password) < s.Length {
+		r, err := randomRune(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, r)
+	}
+	// Shuffle so the guaranteed characters are not at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// GeneratePassphrase generates a random Passphrase from the words of wordlist
+func GeneratePassphrase(s PassphraseGeneratorSettings, wordlist []string) (string, error) {
+	if len(wordlist) < 2 {
+		return "", fmt.Errorf("the word list needs at least 2 words")
+	}
+	if s.Words < 1 {
+		return "", fmt.Errorf("invalid number of words %v", s.Words)
+	}
+	words := make([]string, s.Words)
+	for i := range words {
+		n, err := randomInt(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		word := wordlist[n]
+		switch s.WordCase {
+		case "", "lowercase":
+			word = strings.ToLower(word)
+		case "uppercase":
+			word = strings.ToUpper(word)
+		case "camelcase":
+			w := []rune(strings.ToLower(word))
+			w[0] = unicode.ToUpper(w[0])
+			word = string(w)
+		default:
+			return "", fmt.Errorf("unknown word case %q, must be lowercase, uppercase or camelcase", s.WordCase)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, s.WordSeparator), nil
+}
+
+// ReadWordlist reads a word list with one word per line. Lines of dice
+// wordlists like the EFF ones ("11111	abacus") use the last column.
+func ReadWordlist(r io.Reader) ([]string, error) {
+	words := []string{}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		word := fields[len(fields)-1]
+		if !seen[word] {
+			seen[word] = true
+			words = append(words, word)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading word list: %w", err)
+	}
+	return words, nil
+}
+
+func randomRune(chars []rune) (rune, error) {
+	n, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[n], nil
+}
+
+func randomInt(max int) (int, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, fmt.Errorf("generating random number: %w", err)
+	}
+	return int(n.Int64()), nil
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	s := DefaultPasswordPolicy.PasswordGeneratorSettings
+	for i := 0; i < 100; i++ {
+		password, err := GeneratePassword(s, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if utf8.RuneCountInString(password) != s.Length {
+			t.Fatalf("password %q has length %v, want %v", password, len(password), s.Length)
+		}
+		if strings.ContainsAny(password, CharsLookAlike) {
+			t.Fatalf("password %q contains look-alike characters", password)
+		}
+		for _, class := range []string{CharsUpper, CharsLower, CharsDigit} {
+			if !strings.ContainsAny(password, class) {
+				t.Fatalf("password %q has no character of %q", password, class)
+			}
+		}
+	}
+}
+
+func TestGeneratePassword_Classes(t *testing.T) {
+	s := PasswordGeneratorSettings{Length: 64, MaskDigit: true}
+	password, err := GeneratePassword(s, "13579")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Trim(password, "02468") != "" {
+		t.Errorf("password %q contains excluded characters", password)
+	}
+
+	if _, err := GeneratePassword(PasswordGeneratorSettings{Length: 8}, ""); err == nil {
+		t.Error("expected error without character classes")
+	}
+	if _, err := GeneratePassword(s, CharsDigit); err == nil {
+		t.Error("expected error when all characters are excluded")
+	}
+}
+
+func TestPasswordGeneratorSettings_Check(t *testing.T) {
+	s := DefaultPasswordPolicy.PasswordGeneratorSettings
+	if err := s.Check(); err != nil {
+		t.Errorf("default settings: %v", err)
+	}
+	s.Length = 4
+	if err := s.Check(); err == nil {
+		t.Error("expected error for length below min_length")
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	s := PassphraseGeneratorSettings{Words: 5, WordSeparator: "-", WordCase: "camelcase"}
+	passphrase, err := GeneratePassphrase(s, []string{"alpha", "bravo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := strings.Split(passphrase, "-")
+	if len(words) != 5 {
+		t.Fatalf("passphrase %q has %v words, want 5", passphrase, len(words))
+	}
+	for _, w := range words {
+		if w != "Alpha" && w != "Bravo" {
+			t.Errorf("unexpected word %q", w)
+		}
+	}
+
+	s.WordCase = "titlecase"
+	if _, err := GeneratePassphrase(s, []string{"alpha", "bravo"}); err == nil {
+		t.Error("expected error for unknown word case")
+	}
+}
+
+func TestReadWordlist(t *testing.T) {
+	got, err := ReadWordlist(strings.NewReader("11111\tabacus\n11112\tabdomen\n\nabacus\nzebra\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"abacus", "abdomen", "zebra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadWordlist = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,265 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/passbolt/go-passbolt/helper"
+)
+
+// Resolver resolves references to Entities to their IDs. Besides IDs it
+// accepts Folder paths like /Prod/Databases, Resource paths like
+// /Prod/Databases/postgres, Usernames and Group names. The Entities are
+// listed once on first use. It is safe for concurrent use.
+type Resolver struct {
+	client *api.Client
+
+	mu          sync.Mutex
+	folderPaths map[string][]string
+	// resources holds the Resource names by Folder ID, loaded per Folder
+	resources map[string][]resourceName
+	users     []api.User
+	groups    []api.Group
+}
+
+type resourceName struct {
+	id   string
+	name string
+}
+
+// NewResolver returns a Resolver using client
+func NewResolver(client *api.Client) *Resolver {
+	return &Resolver{
+		client:    client,
+		resources: map[string][]resourceName{},
+	}
+}
+
+// isID reports whether ref is an ID rather than a name or path
+func isID(ref string) bool {
+	return uuid.Validate(ref) == nil
+}
+
+// Folder returns the ID of a Folder given by ID or path. An empty ref or "/"
+// is the root and resolves to an empty ID.
+func (r *Resolver) Folder(ctx context.Context, ref string) (string, error) {
+	if ref == "" || isID(ref) {
+		return ref, nil
+	}
+	path := strings.Trim(ref, "/")
+	if path == "" {
+		return "", nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	folderPaths, err := r.getFolderPaths(ctx)
+	if err != nil {
+		return "", err
+	}
+	matches := []string{}
+	for id, p := range folderPaths {
+		if strings.Join(p, "/") == path {
+			matches = append(matches, id)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no Folder found at %q", ref)
+	}
+	return pickOne("Folders", ref, matches)
+}
+
+// Resource returns the ID of a Resource given by ID or by Folder path and
+// name, e.g. /Prod/Databases/postgres. A name without Folder is looked up in the root.
+func (r *Resolver) Resource(ctx context.Context, ref string) (string, error) {
+	if ref == "" || isID(ref) {
+		return ref, nil
+	}
+	parts := strings.Split(strings.Trim(ref, "/"), "/")
+	name := parts[len(parts)-1]
+	if name == "" {
+		return "", fmt.Errorf("invalid Resource reference %q", ref)
+	}
+	folderID, err := r.Folder(ctx, "/"+strings.Join(parts[:len(parts)-1], "/"))
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resources, err := r.getResources(ctx, folderID)
+	if err != nil {
+		return "", err
+	}
+	matches := []string{}
+	for _, res := range resources {
+		if res.name == name {
+			matches = append(matches, res.id)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no Resource named %q found", ref)
+	}
+	return pickOne("Resources", ref, matches)
+}
+
+// User returns the ID of a User given by ID or Username
+func (r *Resolver) User(ctx context.Context, ref string) (string, error) {
+	if ref == "" || isID(ref) {
+		return ref, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.users == nil {
+		users, err := r.client.GetUsers(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("listing User: %w", err)
+		}
+		r.users = users
+	}
+	matches := []string{}
+	for _, u := range r.users {
+		if strings.EqualFold(u.Username, ref) {
+			matches = append(matches, u.ID)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no User with Username %q found", ref)
+	}
+	return pickOne("Users", ref, matches)
+}
+
+// Group returns the ID of a Group given by ID or name
+func (r *Resolver) Group(ctx context.Context, ref string) (string, error) {
+	if ref == "" || isID(ref) {
+		return ref, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.groups == nil {
+		groups, err := r.client.GetGroups(ctx, nil)
+		if err != nil {
+			return "", fmt.Errorf("listing Group: %w", err)
+		}
+		r.groups = groups
+	}
+	matches := []string{}
+	for _, g := range r.groups {
+		if g.Name == ref {
+			matches = append(matches, g.ID)
+		}
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no Group named %q found", ref)
+	}
+	return pickOne("Groups", ref, matches)
+}
+
+// Users resolves multiple Users, see User
+func (r *Resolver) Users(ctx context.Context, refs []string) ([]string, error) {
+	return resolveAll(ctx, refs, r.User)
+}
+
+// Groups resolves multiple Groups, see Group
+func (r *Resolver) Groups(ctx context.Context, refs []string) ([]string, error) {
+	return resolveAll(ctx, refs, r.Group)
+}
+
+func resolveAll(ctx context.Context, refs []string, resolve func(context.Context, string) (string, error)) ([]string, error) {
+	ids := make([]string, len(refs))
+	for i, ref := range refs {
+		id, err := resolve(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func pickOne(entities, ref string, matches []string) (string, error) {
+	if len(matches) > 1 {
+		sort.Strings(matches)
+		return "", fmt.Errorf("%q is ambiguous, it matches the %v %v, use an ID instead", ref, entities, strings.Join(matches, ", "))
+	}
+	return matches[0], nil
+}
+
+// getFolderPaths lists all Folders once, r.mu must be held
+func (r *Resolver) getFolderPaths(ctx context.Context) (map[string][]string, error) {
+	if r.folderPaths == nil {
+		folders, err := r.client.GetFolders(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("listing Folder: %w", err)
+		}
+		r.folderPaths = FolderPaths(folders)
+	}
+	return r.folderPaths, nil
+}
+
+// getResources lists the names of the Resources in a Folder once, r.mu must be held
+func (r *Resolver) getResources(ctx context.Context, folderID string) ([]resourceName, error) {
+	if resources, ok := r.resources[folderID]; ok {
+		return resources, nil
+	}
+	opts := &api.GetResourcesOptions{}
+	if folderID != "" {
+		opts.FilterHasParent = []string{folderID}
+	}
+	resources, err := r.client.GetResources(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("listing Resource: %w", err)
+	}
+
+	var mu sync.Mutex
+	names := []resourceName{}
+	byID := map[string]api.Resource{}
+	ids := []string{}
+	for _, res := range resources {
+		if res.FolderParentID == folderID {
+			byID[res.ID] = res
+			ids = append(ids, res.ID)
+		}
+	}
+	errs := ForEachParallel(ids, func(id string) error {
+		name, err := r.resourceName(ctx, byID[id])
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		names = append(names, resourceName{id: id, name: name})
+		mu.Unlock()
+		return nil
+	})
+	for _, err := range errs {
+		if !errors.Is(err, helper.ErrUnsupportedResourceType) {
+			return nil, fmt.Errorf("get Resource %w", err)
+		}
+	}
+	r.resources[folderID] = names
+	return names, nil
+}
+
+// resourceName decrypts the name of a Resource if needed
+func (r *Resolver) resourceName(ctx context.Context, res api.Resource) (string, error) {
+	rType, err := r.client.GetResourceTypeCached(ctx, res.ResourceTypeID)
+	if err != nil {
+		return "", fmt.Errorf("get ResourceType: %w", err)
+	}
+	if !strings.HasPrefix(rType.Slug, "v5-") {
+		return res.Name, nil
+	}
+	_, metadata, _, err := helper.GetResourceFieldMaps(r.client, res, api.Secret{}, *rType, false)
+	if err != nil {
+		return "", err
+	}
+	return helper.GetStringField(metadata, "name"), nil
+}
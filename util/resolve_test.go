@@ -0,0 +1,53 @@
+package util
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIsID(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"5f1a4e3c-2b6d-4c8e-9a0b-1c2d3e4f5a6b": true,
+		"/Prod/Databases":                      false,
+		"postgres":                             false,
+		"ada@passbolt.com":                     false,
+	} {
+		if got := isID(ref); got != want {
+			t.Errorf("isID(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestPickOne(t *testing.T) {
+	id, err := pickOne("Folders", "/Prod", []string{"a"})
+	if err != nil || id != "a" {
+		t.Errorf("pickOne single match = %q, %v", id, err)
+	}
+
+	_, err = pickOne("Folders", "/Prod", []string{"b", "a"})
+	if err == nil {
+		t.Fatal("expected an error for an ambiguous match")
+	}
+	if !strings.Contains(err.Error(), "Folders a, b") {
+		t.Errorf("error does not list the sorted candidates: %v", err)
+	}
+}
+
+func TestResolverPassesIDsThrough(t *testing.T) {
+	// IDs and the root must resolve without a client
+	r := NewResolver(nil)
+	id := "5f1a4e3c-2b6d-4c8e-9a0b-1c2d3e4f5a6b"
+	for _, resolve := range []func(context.Context, string) (string, error){r.Folder, r.Resource, r.User, r.Group} {
+		got, err := resolve(context.Background(), id)
+		if err != nil || got != id {
+			t.Errorf("resolving an ID = %q, %v", got, err)
+		}
+	}
+	for _, root := range []string{"", "/"} {
+		got, err := r.Folder(context.Background(), root)
+		if err != nil || got != "" {
+			t.Errorf("Folder(%q) = %q, %v, want the root", root, got, err)
+		}
+	}
+}
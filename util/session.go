@@ -0,0 +1,338 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/viper"
+)
+
+// Names of the cookies and header Passbolt uses for a session
+const (
+	sessionCookieName = "passbolt_session"
+	csrfCookieName    = "csrfToken"
+	mfaCookieName     = "passbolt_mfa"
+	csrfHeaderName    = "X-CSRF-Token"
+)
+
+// sessionCache is what is kept between invocations when the session cache is
+// enabled. It is stored encrypted to the users own key.
+//
+// Session and CSRF are used to resume the session without logging in again,
+// with State restoring what Login would have set. MFA is a remembered MFA
+// verification used if the session has expired.
+type sessionCache struct {
+	Server  string      `json:"server"`
+	Expires time.Time   `json:"expires"`
+	Session http.Cookie `json:"session"`
+	CSRF    http.Cookie `json:"csrf"`
+	MFA     http.Cookie `json:"mfa"`
+	State   loginState  `json:"state"`
+}
+
+// loginState is what Login sets on a client and the CLI depends on. A resumed
+// session did not log in, so it is kept in the session cache and read through
+// clientState instead of the client.
+type loginState struct {
+	UserID               string                   `json:"user_id"`
+	MetadataTypeSettings api.MetadataTypeSettings `json:"metadata_type_settings"`
+}
+
+// rememberMFAChallengeResponse asks the Server for an MFA cookie which is not
+// bound to the current session, so it can be cached
+type rememberMFAChallengeResponse struct {
+	api.MFAChallengeResponse
+	Remember bool `json:"remember"`
+}
+
+// resumedClients holds the loginState of the clients whose session is kept in
+// the session cache, they must not be logged out
+var resumedClients sync.Map
+
+// SessionCacheEnabled returns if the session cache is enabled
+func SessionCacheEnabled() bool {
+	return viper.GetBool("sessionCache")
+}
+
+// SessionCachePath returns the file the session cache of the configured Server and Key is stored in
+func SessionCachePath() (string, error) {
+	confDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("getting Config Directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(viper.GetString("serverAddress") + "\n" + viper.GetString("userPrivateKey")))
+	return filepath.Join(confDir, "go-passbolt-cli", "sessions", hex.EncodeToString(sum[:8])+".asc"), nil
+}
+
+// ClearSessionCache deletes the session cache, it returns false if there was none
+func ClearSessionCache() (bool, error) {
+	path, err := SessionCachePath()
+	if err != nil {
+		return false, err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("removing Session Cache: %w", err)
+	}
+	return true, nil
+}
+
+// sessionTransport records the session cookies the Server sets, and once a
+// cached session is resumed adds its cookies and CSRF token to every request,
+// since the client itself has no session then.
+type sessionTransport struct {
+	base http.RoundTripper
+
+	mu      sync.Mutex
+	resumed bool
+	session http.Cookie
+	csrf    http.Cookie
+	mfa     http.Cookie
+}
+
+func (t *sessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.resumed {
+		// RoundTrippers must not modify the request they are given
+		req = req.Clone(req.Context())
+		cookies := req.Cookies()
+		req.Header.Del("Cookie")
+		for _, c := range cookies {
+			switch c.Name {
+			case "", sessionCookieName, csrfCookieName, mfaCookieName:
+				continue
+			}
+			req.AddCookie(c)
+		}
+		for _, c := range []http.Cookie{t.session, t.csrf, t.mfa} {
+			if c.Name != "" {
+				req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+			}
+		}
+		req.Header.Set(csrfHeaderName, t.csrf.Value)
+	}
+	t.mu.Unlock()
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, c := range res.Cookies() {
+		switch c.Name {
+		case sessionCookieName:
+			t.session = *c
+		case csrfCookieName:
+			t.csrf = *c
+		case mfaCookieName:
+			t.mfa = *c
+		}
+	}
+	return res, nil
+}
+
+// resume makes the transport use a cached session
+func (t *sessionTransport) resume(cache *sessionCache) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resumed = true
+	t.session = cache.Session
+	t.csrf = cache.CSRF
+	t.mfa = cache.MFA
+}
+
+// reset stops using a cached session, e.g. because it has expired
+func (t *sessionTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.resumed = false
+	t.session = http.Cookie{}
+	t.csrf = http.Cookie{}
+	t.mfa = http.Cookie{}
+}
+
+// cookies returns the session, CSRF and MFA cookies seen so far
+func (t *sessionTransport) cookies() (session, csrf, mfa http.Cookie) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.session, t.csrf, t.mfa
+}
+
+// addSessionTransport wraps the transport of httpClient so the session can be
+// cached and resumed
+func addSessionTransport(httpClient *http.Client) *sessionTransport {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &sessionTransport{base: base}
+	httpClient.Transport = t
+	return t
+}
+
+// resumeSession tries to resume the cached session, it returns false if there
+// is none or the Server does not accept it anymore. The cached MFA
+// verification is returned in any case, so it can be used for a new login.
+func resumeSession(ctx context.Context, client *api.Client, t *sessionTransport) (bool, http.Cookie) {
+	cache := loadSessionCache(client)
+	if cache == nil {
+		return false, http.Cookie{}
+	}
+	// Without the state Login would have set the session can't be used
+	if cache.Session.Value == "" || cache.CSRF.Value == "" || cache.State.UserID == "" {
+		return false, cache.MFA
+	}
+	t.resume(cache)
+	if !client.CheckSession(ctx) {
+		if viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, "Cached Session has expired")
+		}
+		t.reset()
+		return false, cache.MFA
+	}
+	resumedClients.Store(client, cache.State)
+	return true, cache.MFA
+}
+
+// addCachedMFA wraps the MFA Callback of the client, so that a cached MFA
+// cookie is used once before falling back to the configured MFA mode
+func addCachedMFA(client *api.Client, cached http.Cookie) {
+	callback := client.MFACallback
+	client.MFACallback = func(ctx context.Context, c *api.Client, res *api.APIResponse) (http.Cookie, error) {
+		if cached.Value != "" {
+			cookie := cached
+			// If the Server rejects the cookie we get called again
+			cached = http.Cookie{}
+			return cookie, nil
+		}
+		if callback == nil {
+			return http.Cookie{}, fmt.Errorf("got MFA challenge but no MFA mode is configured")
+		}
+		return callback(ctx, c, res)
+	}
+}
+
+// cacheSession saves the session of a freshly logged in client, so that the
+// following invocations can resume it
+func cacheSession(client *api.Client, t *sessionTransport, cachedMFA http.Cookie) {
+	session, csrf, mfa := t.cookies()
+	if mfa.Value == "" {
+		mfa = cachedMFA
+	}
+	state := loginState{
+		UserID:               client.GetUserID(),
+		MetadataTypeSettings: client.MetadataTypeSettings(),
+	}
+	err := saveSessionCache(client, sessionCache{Session: session, CSRF: csrf, MFA: mfa, State: state})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save session cache: %v\n", err)
+		return
+	}
+	resumedClients.Store(client, state)
+}
+
+func loadSessionCache(client *api.Client) *sessionCache {
+	path, err := SessionCachePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) && viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, "Reading Session Cache:", err)
+		}
+		return nil
+	}
+	decrypted, err := client.DecryptMessage(string(data))
+	if err != nil {
+		if viper.GetBool("debug") {
+			fmt.Fprintln(os.Stderr, "Decrypting Session Cache:", err)
+		}
+		return nil
+	}
+	var cache sessionCache
+	err = json.Unmarshal([]byte(decrypted), &cache)
+	if err != nil || cache.Server != viper.GetString("serverAddress") || time.Now().After(cache.Expires) {
+		return nil
+	}
+	return &cache
+}
+
+// saveSessionCache saves cache with the Server and expiry set
+func saveSessionCache(client *api.Client, cache sessionCache) error {
+	cache.Server = viper.GetString("serverAddress")
+	cache.Expires = time.Now().Add(viper.GetDuration("sessionCacheTTL"))
+	for _, c := range []http.Cookie{cache.Session, cache.MFA} {
+		if !c.Expires.IsZero() && c.Expires.Before(cache.Expires) {
+			cache.Expires = c.Expires
+		}
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("marshalling Session Cache: %w", err)
+	}
+	encrypted, err := client.EncryptMessage(string(data))
+	if err != nil {
+		return fmt.Errorf("encrypting Session Cache: %w", err)
+	}
+	path, err := SessionCachePath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return fmt.Errorf("creating Session Cache Directory: %w", err)
+	}
+	return os.WriteFile(path, []byte(encrypted), 0600)
+}
+
+// clientState returns the loginState of client, for a resumed session the
+// one restored from the session cache. The CLI reads it only through
+// CurrentUserID, MetadataTypeSettings and DefaultResourceTypeIsV5.
+func clientState(client *api.Client) loginState {
+	if state, ok := resumedClients.Load(client); ok {
+		return state.(loginState)
+	}
+	return loginState{
+		UserID:               client.GetUserID(),
+		MetadataTypeSettings: client.MetadataTypeSettings(),
+	}
+}
+
+// SessionResumed returns if client uses a cached session instead of having
+// logged in. Library helpers that read what Login sets on the client must
+// not be used with it, see loginState.
+func SessionResumed(client *api.Client) bool {
+	_, ok := resumedClients.Load(client)
+	return ok
+}
+
+// CurrentUserID returns the ID of the logged in User
+func CurrentUserID(client *api.Client) string {
+	return clientState(client).UserID
+}
+
+// MetadataTypeSettings returns the metadata type settings of the Server
+func MetadataTypeSettings(client *api.Client) api.MetadataTypeSettings {
+	return clientState(client).MetadataTypeSettings
+}
+
+// DefaultResourceTypeIsV5 returns if the Server creates v5 Resources by default
+func DefaultResourceTypeIsV5(client *api.Client) bool {
+	return MetadataTypeSettings(client).DefaultResourceType == api.PassboltAPIVersionTypeV5
+}
@@ -0,0 +1,160 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/passbolt/go-passbolt/api"
+	"github.com/spf13/viper"
+)
+
+func TestRememberMFAChallengeResponse(t *testing.T) {
+	data, err := json.Marshal(rememberMFAChallengeResponse{
+		MFAChallengeResponse: api.MFAChallengeResponse{TOTP: "123456"},
+		Remember:             true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	json.Unmarshal(data, &got)
+	if got["totp"] != "123456" || got["remember"] != true {
+		t.Errorf("marshalled %s", data)
+	}
+}
+
+func TestClearSessionCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	viper.Set("serverAddress", "https://passbolt.example.com")
+	defer viper.Set("serverAddress", "")
+
+	removed, err := ClearSessionCache()
+	if err != nil || removed {
+		t.Fatalf("clearing missing cache = %v, %v", removed, err)
+	}
+
+	path, err := SessionCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Dir(path), 0700)
+	os.WriteFile(path, []byte("cache"), 0600)
+
+	viper.Set("serverAddress", "https://other.example.com")
+	if other, _ := SessionCachePath(); other == path {
+		t.Error("cache path does not depend on the server")
+	}
+	viper.Set("serverAddress", "https://passbolt.example.com")
+
+	removed, err = ClearSessionCache()
+	if err != nil || !removed {
+		t.Fatalf("clearing cache = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("cache still exists: %v", err)
+	}
+}
+
+func TestSessionTransport(t *testing.T) {
+	var got *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "new-session"})
+		http.SetCookie(w, &http.Cookie{Name: csrfCookieName, Value: "new-csrf"})
+	}))
+	defer server.Close()
+
+	httpClient := server.Client()
+	transport := addSessionTransport(httpClient)
+
+	// Without a resumed session requests are passed on unchanged
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.AddCookie(&http.Cookie{Name: sessionCookieName, Value: "own"})
+	if _, err := httpClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := got.Cookie(sessionCookieName); err != nil || c.Value != "own" {
+		t.Errorf("session cookie = %v, %v", c, err)
+	}
+	session, csrf, _ := transport.cookies()
+	if session.Value != "new-session" || csrf.Value != "new-csrf" {
+		t.Errorf("recorded cookies %v %v", session, csrf)
+	}
+
+	// A resumed session replaces the empty cookies of the client
+	transport.resume(&sessionCache{
+		Session: http.Cookie{Name: sessionCookieName, Value: "cached"},
+		CSRF:    http.Cookie{Name: csrfCookieName, Value: "token"},
+		MFA:     http.Cookie{Name: mfaCookieName, Value: "mfa"},
+	})
+	req, _ = http.NewRequest("POST", server.URL, nil)
+	req.AddCookie(&http.Cookie{})
+	req.AddCookie(&http.Cookie{Name: "other", Value: "kept"})
+	req.Header.Set(csrfHeaderName, "")
+	if _, err := httpClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{sessionCookieName: "cached", csrfCookieName: "token", mfaCookieName: "mfa", "other": "kept"}
+	for name, value := range want {
+		if c, err := got.Cookie(name); err != nil || c.Value != value {
+			t.Errorf("cookie %v = %v, %v, want %v", name, c, err, value)
+		}
+	}
+	if len(got.Cookies()) != len(want) {
+		t.Errorf("unexpected cookies %v", got.Cookies())
+	}
+	if got.Header.Get(csrfHeaderName) != "token" {
+		t.Errorf("csrf header = %q", got.Header.Get(csrfHeaderName))
+	}
+}
+
+func TestResumedClientState(t *testing.T) {
+	client := &api.Client{}
+	state := loginState{
+		UserID:               "8e3874ae-4b40-590b-968a-418f704b9d9a",
+		MetadataTypeSettings: api.MetadataTypeSettings{DefaultResourceType: api.PassboltAPIVersionTypeV5},
+	}
+
+	// The state survives the session cache
+	data, err := json.Marshal(sessionCache{State: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cache sessionCache
+	if err := json.Unmarshal(data, &cache); err != nil || cache.State != state {
+		t.Fatalf("cached state = %+v, %v", cache.State, err)
+	}
+
+	// resumeSession stores it for the client it resumed
+	resumedClients.Store(client, cache.State)
+	defer resumedClients.Delete(client)
+
+	if !SessionResumed(client) || CurrentUserID(client) != state.UserID || !DefaultResourceTypeIsV5(client) {
+		t.Errorf("resumed client state = %v, %v, %v", SessionResumed(client), CurrentUserID(client), DefaultResourceTypeIsV5(client))
+	}
+
+	// Creating a Resource picks the v5 type from the restored settings
+	viper.Set("dryRun", true)
+	defer viper.Set("dryRun", false)
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	_, err = CreateImportEntry(context.Background(), client, "", ImportEntry{Name: "db", Password: "secret"})
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+	if err != nil || !strings.Contains(string(out), "of type v5-default") {
+		t.Errorf("CreateImportEntry = %q, %v", out, err)
+	}
+}